@@ -18,6 +18,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/retry"
 )
 
 var (
@@ -125,12 +126,20 @@ type ResearchSessionSpec struct {
 	DisplayName string      `json:"displayName"`
 	LLMSettings LLMSettings `json:"llmSettings"`
 	Timeout     int         `json:"timeout"`
+	Retries     *int        `json:"retries,omitempty"`
 }
 
 type LLMSettings struct {
-	Model       string  `json:"model"`
-	Temperature float64 `json:"temperature"`
-	MaxTokens   int     `json:"maxTokens"`
+	Model           string     `json:"model"`
+	Temperature     float64    `json:"temperature"`
+	MaxTokens       int        `json:"maxTokens"`
+	APIKeySecretRef *SecretRef `json:"apiKeySecretRef,omitempty"`
+}
+
+// SecretRef points at a single key of a Secret in the session's namespace
+type SecretRef struct {
+	Name string `json:"name"`
+	Key  string `json:"key"`
 }
 
 type MessageObject struct {
@@ -141,15 +150,28 @@ type MessageObject struct {
 	ToolUseIsError *bool  `json:"tool_use_is_error,omitempty"`
 }
 
+// ResearchResult is the runner's answer, written to status.result on completion
+type ResearchResult struct {
+	Output     string `json:"output"`
+	WebsiteURL string `json:"websiteURL,omitempty"`
+}
+
 type ResearchSessionStatus struct {
-	Phase          string          `json:"phase,omitempty"`
-	Message        string          `json:"message,omitempty"`
-	StartTime      *string         `json:"startTime,omitempty"`
-	CompletionTime *string         `json:"completionTime,omitempty"`
-	JobName        string          `json:"jobName,omitempty"`
-	FinalOutput    string          `json:"finalOutput,omitempty"`
-	Cost           *float64        `json:"cost,omitempty"`
-	Messages       []MessageObject `json:"messages,omitempty"`
+	Phase              string          `json:"phase,omitempty"`
+	Message            string          `json:"message,omitempty"`
+	StartTime          *string         `json:"startTime,omitempty"`
+	CompletionTime     *string         `json:"completionTime,omitempty"`
+	JobName            string          `json:"jobName,omitempty"`
+	ObservedGeneration *int64          `json:"observedGeneration,omitempty"`
+	FailedPod          string          `json:"failedPod,omitempty"`
+	ExitCode           *int            `json:"exitCode,omitempty"`
+	FinalOutput        string          `json:"finalOutput,omitempty"`
+	Result             *ResearchResult `json:"result,omitempty"`
+	RunnerImage        string          `json:"runnerImage,omitempty"`
+	DurationSeconds    *int            `json:"durationSeconds,omitempty"`
+	RunnerLog          string          `json:"runnerLog,omitempty"`
+	Cost               *float64        `json:"cost,omitempty"`
+	Messages           []MessageObject `json:"messages,omitempty"`
 }
 
 type CreateResearchSessionRequest struct {
@@ -158,6 +180,14 @@ type CreateResearchSessionRequest struct {
 	DisplayName string       `json:"displayName,omitempty"`
 	LLMSettings *LLMSettings `json:"llmSettings,omitempty"`
 	Timeout     *int         `json:"timeout,omitempty"`
+	Retries     *int         `json:"retries,omitempty"`
+	Resources   *Resources   `json:"resources,omitempty"`
+}
+
+// Resources overrides the runner container's resource requests and limits
+type Resources struct {
+	Requests map[string]string `json:"requests,omitempty"`
+	Limits   map[string]string `json:"limits,omitempty"`
 }
 
 // getResearchSessionResource returns the GroupVersionResource for ResearchSession
@@ -256,6 +286,7 @@ func createResearchSession(c *gin.Context) {
 		if req.LLMSettings.MaxTokens != 0 {
 			llmSettings.MaxTokens = req.LLMSettings.MaxTokens
 		}
+		llmSettings.APIKeySecretRef = req.LLMSettings.APIKeySecretRef
 	}
 
 	timeout := 300
@@ -290,6 +321,30 @@ func createResearchSession(c *gin.Context) {
 			"phase": "Pending",
 		},
 	}
+	if req.Retries != nil {
+		session["spec"].(map[string]interface{})["retries"] = *req.Retries
+	}
+	if req.Resources != nil {
+		resources := map[string]interface{}{}
+		for key, values := range map[string]map[string]string{"requests": req.Resources.Requests, "limits": req.Resources.Limits} {
+			if len(values) == 0 {
+				continue
+			}
+			list := map[string]interface{}{}
+			for name, quantity := range values {
+				list[name] = quantity
+			}
+			resources[key] = list
+		}
+		session["spec"].(map[string]interface{})["resources"] = resources
+	}
+	if ref := llmSettings.APIKeySecretRef; ref != nil {
+		spec := session["spec"].(map[string]interface{})
+		spec["llmSettings"].(map[string]interface{})["apiKeySecretRef"] = map[string]interface{}{
+			"name": ref.Name,
+			"key":  ref.Key,
+		}
+	}
 
 	gvr := getResearchSessionResource()
 	obj := &unstructured.Unstructured{Object: session}
@@ -329,6 +384,10 @@ func deleteResearchSession(c *gin.Context) {
 func updateResearchSessionStatus(c *gin.Context) {
 	name := c.Param("name")
 
+	// Runner pods for sessions in other namespaces (operator WATCH_ALL_NAMESPACES)
+	// say which namespace their session lives in
+	sessionNamespace := c.DefaultQuery("namespace", namespace)
+
 	var statusUpdate map[string]interface{}
 	if err := c.ShouldBindJSON(&statusUpdate); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -337,31 +396,40 @@ func updateResearchSessionStatus(c *gin.Context) {
 
 	gvr := getResearchSessionResource()
 
-	// Get current resource
-	item, err := dynamicClient.Resource(gvr).Namespace(namespace).Get(context.TODO(), name, v1.GetOptions{})
-	if err != nil {
-		if errors.IsNotFound(err) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Research session not found"})
-			return
+	// The operator writes status while the job runs (e.g. status.runnerLog), so
+	// a conflict re-reads the session and applies the update again
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		// Get current resource
+		item, err := dynamicClient.Resource(gvr).Namespace(sessionNamespace).Get(context.TODO(), name, v1.GetOptions{})
+		if err != nil {
+			return err
 		}
-		log.Printf("Failed to get research session %s: %v", name, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get research session"})
-		return
-	}
 
-	// Update status
-	if item.Object["status"] == nil {
-		item.Object["status"] = make(map[string]interface{})
-	}
+		// Update status
+		if item.Object["status"] == nil {
+			item.Object["status"] = make(map[string]interface{})
+		}
 
-	status := item.Object["status"].(map[string]interface{})
-	for key, value := range statusUpdate {
-		status[key] = value
-	}
+		status := item.Object["status"].(map[string]interface{})
+		for key, value := range statusUpdate {
+			status[key] = value
+		}
 
-	// Update the resource
-	_, err = dynamicClient.Resource(gvr).Namespace(namespace).Update(context.TODO(), item, v1.UpdateOptions{})
+		// Keep conditions in step with phase changes reported by the runner
+		if phase, ok := statusUpdate["phase"].(string); ok {
+			message, _ := status["message"].(string)
+			setPhaseConditions(status, phase, message)
+		}
+
+		// Status is a subresource, so it is only written through UpdateStatus
+		_, err = dynamicClient.Resource(gvr).Namespace(sessionNamespace).UpdateStatus(context.TODO(), item, v1.UpdateOptions{})
+		return err
+	})
 	if err != nil {
+		if errors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Research session not found"})
+			return
+		}
 		log.Printf("Failed to update research session status %s: %v", name, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update research session status"})
 		return
@@ -418,42 +486,63 @@ func stopResearchSession(c *gin.Context) {
 	name := c.Param("name")
 	gvr := getResearchSessionResource()
 
-	// Get current resource
-	item, err := dynamicClient.Resource(gvr).Namespace(namespace).Get(context.TODO(), name, v1.GetOptions{})
+	// The operator patches status while the job runs, so a conflict re-reads
+	// the session and applies the stop again
+	var currentPhase, jobName string
+	errTerminal := fmt.Errorf("session is already finished")
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		// Get current resource
+		item, err := dynamicClient.Resource(gvr).Namespace(namespace).Get(context.TODO(), name, v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		// Check current status
+		status, ok := item.Object["status"].(map[string]interface{})
+		if !ok {
+			status = make(map[string]interface{})
+			item.Object["status"] = status
+		}
+
+		currentPhase, _ = status["phase"].(string)
+		if currentPhase == "Completed" || currentPhase == "Failed" || currentPhase == "Stopped" {
+			return errTerminal
+		}
+		jobName, _ = status["jobName"].(string)
+
+		// Update status to Stopped
+		status["phase"] = "Stopped"
+		status["message"] = "Research session stopped by user"
+		status["completionTime"] = time.Now().Format(time.RFC3339)
+		setPhaseConditions(status, "Stopped", "Research session stopped by user")
+
+		// Update the status subresource
+		_, err = dynamicClient.Resource(gvr).Namespace(namespace).UpdateStatus(context.TODO(), item, v1.UpdateOptions{})
+		return err
+	})
+	if err == errTerminal {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Cannot stop session in %s state", currentPhase)})
+		return
+	}
 	if err != nil {
 		if errors.IsNotFound(err) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Research session not found"})
 			return
 		}
-		log.Printf("Failed to get research session %s: %v", name, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get research session"})
-		return
-	}
-
-	// Check current status
-	status, ok := item.Object["status"].(map[string]interface{})
-	if !ok {
-		status = make(map[string]interface{})
-		item.Object["status"] = status
-	}
-
-	currentPhase, _ := status["phase"].(string)
-	if currentPhase == "Completed" || currentPhase == "Failed" || currentPhase == "Stopped" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Cannot stop session in %s state", currentPhase)})
+		log.Printf("Failed to update research session status %s: %v", name, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update research session status"})
 		return
 	}
 
-	log.Printf("Attempting to stop research session %s (current phase: %s)", name, currentPhase)
+	log.Printf("Stopped research session %s (previous phase: %s)", name, currentPhase)
 
-	// Get job name from status
-	jobName, jobExists := status["jobName"].(string)
-	if jobExists && jobName != "" {
-		// Delete the job
+	// Delete the job only once the session is Stopped; deleting it first would
+	// let the operator see a Running session without a job and start a new one
+	if jobName != "" {
 		err := k8sClient.BatchV1().Jobs(namespace).Delete(context.TODO(), jobName, v1.DeleteOptions{})
 		if err != nil && !errors.IsNotFound(err) {
 			log.Printf("Failed to delete job %s: %v", jobName, err)
-			// Don't fail the request if job deletion fails - continue with status update
-			log.Printf("Continuing with status update despite job deletion failure")
+			// Don't fail the request if job deletion fails - the session is already Stopped
 		} else {
 			log.Printf("Deleted job %s for research session %s", jobName, name)
 		}
@@ -462,26 +551,6 @@ func stopResearchSession(c *gin.Context) {
 		log.Printf("No job found to delete for research session %s", name)
 	}
 
-	// Update status to Stopped
-	status["phase"] = "Stopped"
-	status["message"] = "Research session stopped by user"
-	status["completionTime"] = time.Now().Format(time.RFC3339)
-
-	// Update the resource
-	_, err = dynamicClient.Resource(gvr).Namespace(namespace).Update(context.TODO(), item, v1.UpdateOptions{})
-	if err != nil {
-		if errors.IsNotFound(err) {
-			// Session was deleted while we were trying to update it
-			log.Printf("Research session %s was deleted during stop operation", name)
-			c.JSON(http.StatusOK, gin.H{"message": "Research session no longer exists (already deleted)"})
-			return
-		}
-		log.Printf("Failed to update research session status %s: %v", name, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update research session status"})
-		return
-	}
-
-	log.Printf("Successfully stopped research session %s", name)
 	c.JSON(http.StatusOK, gin.H{"message": "Research session stopped successfully"})
 }
 
@@ -505,6 +574,10 @@ func parseSpec(spec map[string]interface{}) ResearchSessionSpec {
 		result.Timeout = int(timeout)
 	}
 
+	if retries, ok := intValue(spec["retries"]); ok {
+		result.Retries = &retries
+	}
+
 	if llmSettings, ok := spec["llmSettings"].(map[string]interface{}); ok {
 		if model, ok := llmSettings["model"].(string); ok {
 			result.LLMSettings.Model = model
@@ -515,6 +588,11 @@ func parseSpec(spec map[string]interface{}) ResearchSessionSpec {
 		if maxTokens, ok := llmSettings["maxTokens"].(float64); ok {
 			result.LLMSettings.MaxTokens = int(maxTokens)
 		}
+		if ref, ok := llmSettings["apiKeySecretRef"].(map[string]interface{}); ok {
+			name, _ := ref["name"].(string)
+			key, _ := ref["key"].(string)
+			result.LLMSettings.APIKeySecretRef = &SecretRef{Name: name, Key: key}
+		}
 	}
 
 	return result
@@ -543,10 +621,41 @@ func parseStatus(status map[string]interface{}) *ResearchSessionStatus {
 		result.JobName = jobName
 	}
 
+	// Integers from the API server decode as int64
+	if observedGeneration, ok := status["observedGeneration"].(int64); ok {
+		result.ObservedGeneration = &observedGeneration
+	}
+
+	if failedPod, ok := status["failedPod"].(string); ok {
+		result.FailedPod = failedPod
+	}
+
+	if code, ok := intValue(status["exitCode"]); ok {
+		result.ExitCode = &code
+	}
+
 	if finalOutput, ok := status["finalOutput"].(string); ok {
 		result.FinalOutput = finalOutput
 	}
 
+	if researchResult, ok := status["result"].(map[string]interface{}); ok {
+		output, _ := researchResult["output"].(string)
+		websiteURL, _ := researchResult["websiteURL"].(string)
+		result.Result = &ResearchResult{Output: output, WebsiteURL: websiteURL}
+	}
+
+	if duration, ok := intValue(status["durationSeconds"]); ok {
+		result.DurationSeconds = &duration
+	}
+
+	if runnerImage, ok := status["runnerImage"].(string); ok {
+		result.RunnerImage = runnerImage
+	}
+
+	if runnerLog, ok := status["runnerLog"].(string); ok {
+		result.RunnerLog = runnerLog
+	}
+
 	if cost, ok := status["cost"].(float64); ok {
 		result.Cost = &cost
 	}
@@ -584,3 +693,70 @@ func parseStatus(status map[string]interface{}) *ResearchSessionStatus {
 
 	return result
 }
+
+// setCondition adds or updates a condition in the unstructured status, matching
+// the operator. lastTransitionTime only moves when the condition's status changes.
+func setCondition(status map[string]interface{}, condType, condStatus, reason, message string) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	conditions, _ := status["conditions"].([]interface{})
+
+	for i, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok || cond["type"] != condType {
+			continue
+		}
+		if cond["status"] != condStatus {
+			cond["lastTransitionTime"] = now
+		}
+		cond["status"] = condStatus
+		cond["reason"] = reason
+		cond["message"] = message
+		conditions[i] = cond
+		status["conditions"] = conditions
+		return
+	}
+
+	status["conditions"] = append(conditions, map[string]interface{}{
+		"type":               condType,
+		"status":             condStatus,
+		"reason":             reason,
+		"message":            message,
+		"lastTransitionTime": now,
+	})
+}
+
+// setPhaseConditions updates the Ready/Running/Failed conditions for the phases
+// the backend writes: those reported by the runner and Stopped.
+func setPhaseConditions(status map[string]interface{}, phase, message string) {
+	switch phase {
+	case "Running":
+		setCondition(status, "Running", "True", "JobRunning", message)
+		setCondition(status, "Ready", "False", "JobRunning", message)
+		setCondition(status, "Failed", "False", "JobRunning", message)
+	case "Completed":
+		setCondition(status, "Running", "False", "JobSucceeded", message)
+		setCondition(status, "Ready", "True", "JobSucceeded", message)
+		setCondition(status, "Failed", "False", "JobSucceeded", message)
+	case "Failed":
+		setCondition(status, "Running", "False", "JobFailed", message)
+		setCondition(status, "Ready", "False", "JobFailed", message)
+		setCondition(status, "Failed", "True", "JobFailed", message)
+	case "Stopped":
+		setCondition(status, "Running", "False", "Stopped", message)
+		setCondition(status, "Ready", "False", "Stopped", message)
+		setCondition(status, "Failed", "False", "Stopped", message)
+	}
+}
+
+// intValue reads an integer field from an unstructured object. The dynamic
+// client decodes integers as int64, while values built from JSON elsewhere
+// are float64, so both are accepted.
+func intValue(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int64:
+		return int(n), true
+	case float64:
+		return int(n), true
+	}
+	return 0, false
+}
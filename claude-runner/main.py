@@ -30,7 +30,7 @@ class ClaudeRunner:
     def __init__(self):
         self.session_name = os.getenv("RESEARCH_SESSION_NAME", "")
         self.session_namespace = os.getenv("RESEARCH_SESSION_NAMESPACE", "default")
-        self.prompt = os.getenv("PROMPT", "")
+        self.prompt = self._load_prompt()
         self.website_url = os.getenv("WEBSITE_URL", "")
         self.timeout = int(os.getenv("TIMEOUT", "300"))
         self.backend_api_url = os.getenv(
@@ -46,6 +46,14 @@ class ClaudeRunner:
         logger.info(f"Website URL: {self.website_url}")
         logger.info("Using Claude Code CLI with Playwright MCP")
 
+    def _load_prompt(self) -> str:
+        """Read the prompt from PROMPT_FILE when the operator mounted it, else from PROMPT"""
+        prompt_file = os.getenv("PROMPT_FILE")
+        if prompt_file:
+            with open(prompt_file, encoding="utf-8") as f:
+                return f.read()
+        return os.getenv("PROMPT", "")
+
     async def run_research_session(self):
         """Main method to run the research session"""
         try:
@@ -103,6 +111,7 @@ class ClaudeRunner:
                     "message": "Research completed successfully using Claude Code + Playwright MCP",
                     "completionTime": datetime.now(timezone.utc).isoformat(),
                     "finalOutput": result,
+                    "result": {"output": result, "websiteURL": self.website_url},
                     "cost": cost,
                     "messages": all_messages,
                 }
@@ -122,6 +131,7 @@ class ClaudeRunner:
                 }
             )
 
+            write_termination_message(f"Research failed: {str(e)}")
             sys.exit(1)
 
     async def _verify_browser_setup(self):
@@ -474,13 +484,17 @@ Provide a clear, direct answer to the research question based on what you find o
         """Update the ResearchSession status via the backend API"""
         try:
             url = f"{self.backend_api_url}/research-sessions/{self.session_name}/status"
+            params = {"namespace": self.session_namespace}
 
             logger.info(
                 f"Updating session status: {status_update.get('phase', 'unknown')}"
             )
 
             response = await asyncio.get_event_loop().run_in_executor(
-                None, lambda: requests.put(url, json=status_update, timeout=30)
+                None,
+                lambda: requests.put(
+                    url, params=params, json=status_update, timeout=30
+                ),
             )
 
             if response.status_code != 200:
@@ -495,6 +509,16 @@ Provide a clear, direct answer to the research question based on what you find o
             # Don't raise here as this shouldn't stop the main process
 
 
+def write_termination_message(message: str):
+    """Write a one-line failure reason for the operator to read from the pod status"""
+    path = os.getenv("TERMINATION_MESSAGE_PATH", "/dev/termination-log")
+    try:
+        with open(path, "w") as f:
+            f.write(" ".join(message.split())[:1024])
+    except Exception as e:
+        logger.warning(f"Could not write termination message to {path}: {e}")
+
+
 async def main():
     """Main entry point"""
     logger.info("Claude Research Runner with Claude Code + Playwright MCP starting...")
@@ -502,16 +526,20 @@ async def main():
     # Validate required environment variables
     required_vars = [
         "RESEARCH_SESSION_NAME",
-        "PROMPT",
         "WEBSITE_URL",
         "ANTHROPIC_API_KEY",
     ]
     missing_vars = [var for var in required_vars if not os.getenv(var)]
+    if not os.getenv("PROMPT") and not os.getenv("PROMPT_FILE"):
+        missing_vars.append("PROMPT (or PROMPT_FILE)")
 
     if missing_vars:
         logger.error(
             f"Missing required environment variables: {', '.join(missing_vars)}"
         )
+        write_termination_message(
+            f"Missing required environment variables: {', '.join(missing_vars)}"
+        )
         sys.exit(1)
 
     try:
@@ -524,6 +552,7 @@ async def main():
 
     except Exception as e:
         logger.error(f"Unexpected error: {str(e)}")
+        write_termination_message(f"Unexpected error: {str(e)}")
         sys.exit(1)
 
 
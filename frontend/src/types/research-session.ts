@@ -22,13 +22,23 @@ export type MessageObject = {
 	tool_use_is_error?: boolean;
 };
 
+export type ResearchResult = {
+	output: string;
+	websiteURL?: string;
+};
+
 export type ResearchSessionStatus = {
 	phase: ResearchSessionPhase;
 	message?: string;
 	startTime?: string;
 	completionTime?: string;
 	jobName?: string;
+	observedGeneration?: number;
 	finalOutput?: string;
+	result?: ResearchResult;
+	runnerImage?: string;
+	durationSeconds?: number;
+	runnerLog?: string;
 	cost?: number;
 	messages?: MessageObject[];
 };
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+// useFakeClients points the operator's clients at in-memory fakes seeded with
+// objs, restoring the real ones when the test ends. Unstructured objects go to
+// the dynamic client, everything else to the typed one. Job monitors the test
+// started are stopped first so none outlives its clients.
+func useFakeClients(t *testing.T, objs ...runtime.Object) (*dynamicfake.FakeDynamicClient, *fake.Clientset) {
+	t.Helper()
+
+	var sessions, typed []runtime.Object
+	for _, obj := range objs {
+		if _, ok := obj.(*unstructured.Unstructured); ok {
+			sessions = append(sessions, obj)
+		} else {
+			typed = append(typed, obj)
+		}
+	}
+
+	dyn := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{getResearchSessionResource(): "ResearchSessionList"}, sessions...)
+	k8s := fake.NewSimpleClientset(typed...)
+
+	prevDynamic, prevK8s, prevRecorder, prevTimeout := dynamicClient, k8sClient, eventRecorder, apiTimeout
+	dynamicClient, k8sClient, eventRecorder, apiTimeout = dyn, k8s, record.NewFakeRecorder(100), 10*time.Second
+	t.Cleanup(func() {
+		stopMonitors()
+		if !waitForMonitors(5 * time.Second) {
+			t.Error("job monitors did not stop")
+		}
+		dynamicClient, k8sClient, eventRecorder, apiTimeout = prevDynamic, prevK8s, prevRecorder, prevTimeout
+	})
+	return dyn, k8s
+}
+
+// useOperatorConfig applies the settings main reads from the environment,
+// with their defaults, for the duration of the test, so sessions can be
+// reconciled through to a job. Monitors poll every pollInterval.
+func useOperatorConfig(t *testing.T, pollInterval time.Duration) {
+	t.Helper()
+	prevImage := claudeRunnerImage.Load()
+	prevMax, prevTruncate, prevFile := maxPromptBytes, promptTruncate, promptFileBytes
+	prevTimeout, prevTTL, prevPoll := maxSessionTimeout, jobTTLSeconds, jobPollInterval.Load()
+	t.Cleanup(func() {
+		claudeRunnerImage.Store(prevImage)
+		maxPromptBytes, promptTruncate, promptFileBytes = prevMax, prevTruncate, prevFile
+		maxSessionTimeout, jobTTLSeconds = prevTimeout, prevTTL
+		jobPollInterval.Store(prevPoll)
+	})
+
+	image := "quay.io/example/claude-runner:test"
+	claudeRunnerImage.Store(&image)
+	maxPromptBytes, promptTruncate, promptFileBytes = 128*1024, false, 32*1024
+	maxSessionTimeout, jobTTLSeconds = 1800, 3600
+	jobPollInterval.Store(int64(pollInterval))
+}
+
+// apiKeySecret is the default API key secret runner jobs read from.
+func apiKeySecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: v1.ObjectMeta{Name: defaultAPIKeySecretName, Namespace: "default"},
+		Data:       map[string][]byte{defaultAPIKeySecretKey: []byte("key")},
+	}
+}
+
+// newSession builds a ResearchSession with the given spec and status.
+func newSession(name string, spec, status map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "research.example.com/v1",
+		"kind":       "ResearchSession",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": "default",
+			"uid":       name + "-uid",
+		},
+	}}
+	if spec != nil {
+		obj.Object["spec"] = spec
+	}
+	if status != nil {
+		obj.Object["status"] = status
+	}
+	return obj
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// queuedRequeueInterval is how often Pending sessions waiting for a job slot are rechecked.
+const queuedRequeueInterval = 15 * time.Second
+
+const queuedMessage = "Queued: waiting for a free job slot"
+
+// errQueued is returned by the reconcile path when a session was held
+// because MAX_CONCURRENT_JOBS runner jobs are already active.
+var errQueued = errors.New("concurrent job limit reached")
+
+// maxConcurrentJobs caps active runner jobs across the watched namespaces; 0
+// means no limit. CONFIG_DIR can change it at runtime.
+var maxConcurrentJobs atomic.Int64
+
+func initConcurrencyLimit() {
+	n, err := parseMaxConcurrentJobs(os.Getenv("MAX_CONCURRENT_JOBS"))
+	if err != nil {
+		fatal("Invalid MAX_CONCURRENT_JOBS", "err", err)
+	}
+	maxConcurrentJobs.Store(n)
+	if n > 0 {
+		slog.Info("Limiting concurrent runner jobs", "maxConcurrentJobs", n)
+	}
+}
+
+// parseMaxConcurrentJobs reads a MAX_CONCURRENT_JOBS value, defaulting to no
+// limit when empty.
+func parseMaxConcurrentJobs(v string) (int64, error) {
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%q must be a non-negative integer", v)
+	}
+	return n, nil
+}
+
+// atJobLimit reports whether starting another runner job would exceed
+// maxConcurrentJobs. Jobs are counted from the API rather than from monitors,
+// which only exist on the leader and restart empty.
+func atJobLimit(ctx context.Context) (bool, error) {
+	limit := maxConcurrentJobs.Load()
+	if limit == 0 {
+		return false, nil
+	}
+
+	reqCtx, cancel := apiContext(ctx)
+	defer cancel()
+	jobs, err := k8sClient.BatchV1().Jobs(watchNamespace()).List(reqCtx, v1.ListOptions{LabelSelector: "app=claude-runner"})
+	if err != nil {
+		return false, fmt.Errorf("failed to list runner jobs: %v", err)
+	}
+
+	var active int64
+	for i := range jobs.Items {
+		if !jobFinished(&jobs.Items[i]) {
+			active++
+		}
+	}
+	return active >= limit, nil
+}
+
+// jobFinished reports whether the job has succeeded or terminally failed.
+func jobFinished(job *batchv1.Job) bool {
+	return job.Status.Succeeded > 0 || jobFailedCondition(job) != nil
+}
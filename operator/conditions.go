@@ -0,0 +1,85 @@
+package main
+
+import (
+	"time"
+)
+
+// Condition types maintained on ResearchSession status alongside phase
+const (
+	conditionReady   = "Ready"
+	conditionRunning = "Running"
+	conditionFailed  = "Failed"
+
+	// conditionSpecValid says whether the spec passed validation, separating
+	// "the spec is wrong" from "the run failed"
+	conditionSpecValid = "SpecValid"
+)
+
+// conditionUpdate is a condition to set alongside a status update.
+type conditionUpdate struct {
+	condType, status, reason, message string
+}
+
+// setCondition adds or updates a condition in the unstructured status.
+// lastTransitionTime only moves when the condition's status changes.
+func setCondition(status map[string]interface{}, condType, condStatus, reason, message string) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	conditions, _ := status["conditions"].([]interface{})
+
+	for i, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok || cond["type"] != condType {
+			continue
+		}
+		if cond["status"] != condStatus {
+			cond["lastTransitionTime"] = now
+		}
+		cond["status"] = condStatus
+		cond["reason"] = reason
+		cond["message"] = message
+		conditions[i] = cond
+		status["conditions"] = conditions
+		return
+	}
+
+	status["conditions"] = append(conditions, map[string]interface{}{
+		"type":               condType,
+		"status":             condStatus,
+		"reason":             reason,
+		"message":            message,
+		"lastTransitionTime": now,
+	})
+}
+
+// setPhaseConditions updates the Ready/Running/Failed conditions to match a phase transition.
+func setPhaseConditions(status map[string]interface{}, phase, message string) {
+	switch phase {
+	case "Pending":
+		setCondition(status, conditionRunning, "False", "Pending", message)
+		setCondition(status, conditionReady, "False", "Pending", message)
+		setCondition(status, conditionFailed, "False", "Pending", message)
+	case "Creating":
+		setCondition(status, conditionRunning, "False", "CreatingJob", message)
+		setCondition(status, conditionReady, "False", "CreatingJob", message)
+	case "Running":
+		setCondition(status, conditionRunning, "True", "JobRunning", message)
+		setCondition(status, conditionReady, "False", "JobRunning", message)
+		setCondition(status, conditionFailed, "False", "JobRunning", message)
+	case "Completed":
+		setCondition(status, conditionRunning, "False", "JobSucceeded", message)
+		setCondition(status, conditionReady, "True", "JobSucceeded", message)
+		setCondition(status, conditionFailed, "False", "JobSucceeded", message)
+	case "Failed":
+		setCondition(status, conditionRunning, "False", "JobFailed", message)
+		setCondition(status, conditionReady, "False", "JobFailed", message)
+		setCondition(status, conditionFailed, "True", "JobFailed", message)
+	case "Error":
+		setCondition(status, conditionRunning, "False", "JobCreationFailed", message)
+		setCondition(status, conditionReady, "False", "JobCreationFailed", message)
+		setCondition(status, conditionFailed, "True", "JobCreationFailed", message)
+	case "Stopped":
+		setCondition(status, conditionRunning, "False", "Stopped", message)
+		setCondition(status, conditionReady, "False", "Stopped", message)
+		setCondition(status, conditionFailed, "False", "Stopped", message)
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// sessionCondition fetches the session and returns its condType condition.
+func sessionCondition(t *testing.T, name, condType string) map[string]interface{} {
+	t.Helper()
+	obj, err := dynamicClient.Resource(getResearchSessionResource()).Namespace("default").Get(context.Background(), name, v1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		if cond, ok := c.(map[string]interface{}); ok && cond["type"] == condType {
+			return cond
+		}
+	}
+	return nil
+}
+
+func TestInvalidSpecSetsSpecValidFalse(t *testing.T) {
+	session := newSession("s1", map[string]interface{}{
+		"prompt":     "Summarize the site",
+		"websiteURL": "https://example.com",
+		"timeout":    "five minutes",
+	}, map[string]interface{}{"phase": "Pending"})
+	useFakeClients(t, session)
+
+	if err := handleResearchSessionEvent(context.Background(), session); err != nil {
+		t.Fatal(err)
+	}
+
+	cond := sessionCondition(t, "s1", conditionSpecValid)
+	if cond == nil {
+		t.Fatal("SpecValid condition not set")
+	}
+	if cond["status"] != "False" || cond["reason"] != "InvalidTimeout" {
+		t.Errorf("SpecValid = %v/%v, want False/InvalidTimeout", cond["status"], cond["reason"])
+	}
+}
+
+func TestJobFailureLeavesSpecValidTrue(t *testing.T) {
+	useFakeClients(t, newSession("s1", nil, map[string]interface{}{"phase": "Pending"}))
+
+	// The status writes handleResearchSessionEvent and monitorJob make for a
+	// valid spec whose job then fails
+	if err := updateResearchSessionStatus("default", "s1", map[string]interface{}{"phase": "Creating"},
+		conditionUpdate{conditionSpecValid, "True", "Valid", "Spec passed validation"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := updateResearchSessionStatus("default", "s1", map[string]interface{}{"phase": "Failed", "message": "Job failed"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if cond := sessionCondition(t, "s1", conditionSpecValid); cond == nil || cond["status"] != "True" {
+		t.Errorf("SpecValid = %v, want True", cond)
+	}
+	if cond := sessionCondition(t, "s1", conditionFailed); cond == nil || cond["status"] != "True" {
+		t.Errorf("Failed = %v, want True", cond)
+	}
+}
+
+func TestSpecErrorReason(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{specTypeError([]string{"timeout"}, "an integer", "x"), "InvalidTimeout"},
+		{specTypeError([]string{"imagePullSecrets[0]", "name"}, "a string", 1.0), "InvalidImagePullSecrets"},
+		{specTypeError(nil, "an object", "x"), "InvalidSpec"},
+		{invalidSpec("websiteURL", "blocked"), "InvalidWebsiteURL"},
+	}
+	for _, tt := range tests {
+		if got := specErrorReason(tt.err); got != tt.want {
+			t.Errorf("specErrorReason(%q) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestSetConditionKeepsTransitionTime(t *testing.T) {
+	status := map[string]interface{}{"conditions": []interface{}{map[string]interface{}{
+		"type":               conditionRunning,
+		"status":             "True",
+		"reason":             "JobRunning",
+		"lastTransitionTime": "2026-01-01T00:00:00Z",
+	}}}
+
+	// Same status: only the reason and message move
+	setCondition(status, conditionRunning, "True", "JobRunning", "still running")
+	cond := status["conditions"].([]interface{})[0].(map[string]interface{})
+	if cond["lastTransitionTime"] != "2026-01-01T00:00:00Z" || cond["message"] != "still running" {
+		t.Errorf("unchanged status: got %v", cond)
+	}
+
+	setCondition(status, conditionRunning, "False", "Stopped", "stopped by user")
+	if cond["lastTransitionTime"] == "2026-01-01T00:00:00Z" {
+		t.Error("lastTransitionTime did not move when the status changed")
+	}
+	if n := len(status["conditions"].([]interface{})); n != 1 {
+		t.Errorf("%d conditions, want the existing one updated in place", n)
+	}
+}
+
+func TestSetPhaseConditions(t *testing.T) {
+	tests := []struct {
+		phase                  string
+		running, ready, failed string
+	}{
+		{phase: "Running", running: "True", ready: "False", failed: "False"},
+		{phase: "Completed", running: "False", ready: "True", failed: "False"},
+		{phase: "Failed", running: "False", ready: "False", failed: "True"},
+		{phase: "Stopped", running: "False", ready: "False", failed: "False"},
+	}
+	for _, tt := range tests {
+		// Start from a running session so every phase is a transition
+		status := map[string]interface{}{}
+		setPhaseConditions(status, "Running", "")
+		setPhaseConditions(status, tt.phase, "")
+
+		got := map[string]interface{}{}
+		for _, c := range status["conditions"].([]interface{}) {
+			cond := c.(map[string]interface{})
+			got[cond["type"].(string)] = cond["status"]
+		}
+		if got[conditionRunning] != tt.running || got[conditionReady] != tt.ready || got[conditionFailed] != tt.failed {
+			t.Errorf("%s: Running/Ready/Failed = %v/%v/%v, want %s/%s/%s", tt.phase,
+				got[conditionRunning], got[conditionReady], got[conditionFailed], tt.running, tt.ready, tt.failed)
+		}
+	}
+}
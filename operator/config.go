@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+const defaultRunnerImage = "quay.io/gkrumbach07/claude-runner:latest"
+
+// reloadableSettings are the settings CONFIG_DIR can change without a restart.
+// Each parses a raw value, "" meaning unset, and returns a func that applies
+// it, so a reload can validate every setting before applying any.
+var reloadableSettings = map[string]func(string) (func(), error){
+	"LOG_LEVEL": func(v string) (func(), error) {
+		level, err := parseLogLevel(v)
+		if err != nil {
+			return nil, err
+		}
+		return func() { logLevel.Set(level) }, nil
+	},
+	"MAX_CONCURRENT_JOBS": func(v string) (func(), error) {
+		n, err := parseMaxConcurrentJobs(v)
+		if err != nil {
+			return nil, err
+		}
+		return func() { maxConcurrentJobs.Store(n) }, nil
+	},
+	"JOB_POLL_INTERVAL": func(v string) (func(), error) {
+		d, err := parseJobPollInterval(v)
+		if err != nil {
+			return nil, err
+		}
+		return func() { jobPollInterval.Store(int64(d)) }, nil
+	},
+	"CLAUDE_RUNNER_IMAGE": func(v string) (func(), error) {
+		image := parseRunnerImage(v)
+		return func() { claudeRunnerImage.Store(&image) }, nil
+	},
+}
+
+// parseRunnerImage reads a CLAUDE_RUNNER_IMAGE value, using the default image when empty.
+func parseRunnerImage(v string) string {
+	if v == "" {
+		return defaultRunnerImage
+	}
+	return v
+}
+
+// parseJobPollInterval reads a JOB_POLL_INTERVAL value, defaulting to 10s when empty.
+func parseJobPollInterval(v string) (time.Duration, error) {
+	if v == "" {
+		return 10 * time.Second, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%q must be a positive duration like 10s", v)
+	}
+	return d, nil
+}
+
+// configDir holds the mounted ConfigMap, one file per setting; empty disables it
+var configDir string
+
+// appliedConfig is the raw value of each reloadable setting currently in effect
+var appliedConfig map[string]string
+
+// initRuntimeConfig applies CONFIG_DIR on top of the environment and reloads
+// it whenever the mounted ConfigMap changes, until ctx is cancelled.
+func initRuntimeConfig(ctx context.Context) {
+	configDir = os.Getenv("CONFIG_DIR")
+	if configDir == "" {
+		return
+	}
+	if err := reloadConfig(); err != nil {
+		fatal("Invalid configuration", "dir", configDir, "err", err)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		fatal("Failed to watch configuration", "dir", configDir, "err", err)
+	}
+	// ConfigMap volumes are updated by swapping a symlink inside the
+	// directory, so watch the directory rather than the files
+	if err := watcher.Add(configDir); err != nil {
+		fatal("Failed to watch configuration", "dir", configDir, "err", err)
+	}
+	slog.Info("Watching for configuration changes", "dir", configDir)
+
+	go func() {
+		defer watcher.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if err := reloadConfig(); err != nil {
+					slog.Error("Ignoring invalid configuration, keeping previous values", "dir", configDir, "err", err)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				slog.Error("Configuration watch error", "dir", configDir, "err", err)
+			}
+		}
+	}()
+}
+
+// reloadConfig reads every reloadable setting from configDir, falling back to
+// the environment for settings without a file, and applies them only if all
+// are valid.
+func reloadConfig() error {
+	values := map[string]string{}
+	var applies []func()
+	for name, parse := range reloadableSettings {
+		value := os.Getenv(name)
+		data, err := os.ReadFile(filepath.Join(configDir, name))
+		if err == nil {
+			value = strings.TrimSpace(string(data))
+		} else if !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read %s: %v", name, err)
+		}
+
+		apply, err := parse(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %v", name, err)
+		}
+		values[name] = value
+		applies = append(applies, apply)
+	}
+
+	for _, apply := range applies {
+		apply()
+	}
+
+	var changed []string
+	for name, value := range values {
+		if previous, ok := appliedConfig[name]; !ok || previous != value {
+			changed = append(changed, name)
+		}
+	}
+	appliedConfig = values
+	if len(changed) > 0 {
+		sort.Strings(changed)
+		slog.Info("Applied configuration", "dir", configDir, "changed", strings.Join(changed, ","))
+	}
+	return nil
+}
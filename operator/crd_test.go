@@ -0,0 +1,105 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+)
+
+// loadCRDSchema returns the openAPIV3Schema of the ResearchSession CRD manifest.
+func loadCRDSchema(t *testing.T) map[string]interface{} {
+	t.Helper()
+	data, err := os.ReadFile("../manifests/crd.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var crd struct {
+		Spec struct {
+			Versions []struct {
+				Schema struct {
+					OpenAPIV3Schema map[string]interface{} `json:"openAPIV3Schema"`
+				} `json:"schema"`
+			} `json:"versions"`
+		} `json:"spec"`
+	}
+	if err := yaml.Unmarshal(data, &crd); err != nil {
+		t.Fatal(err)
+	}
+	if len(crd.Spec.Versions) == 0 {
+		t.Fatal("CRD has no versions")
+	}
+	return crd.Spec.Versions[0].Schema.OpenAPIV3Schema
+}
+
+// schemaHasPath reports whether the dotted path is declared in schema, so the
+// API server neither prunes it nor lets a typo of it through.
+func schemaHasPath(schema map[string]interface{}, path string) bool {
+	node := schema
+	for _, field := range strings.Split(path, ".") {
+		properties, _ := node["properties"].(map[string]interface{})
+		next, ok := properties[field].(map[string]interface{})
+		if !ok {
+			return false
+		}
+		node = next
+	}
+	return true
+}
+
+func TestCRDSchemaCoversOperatorFields(t *testing.T) {
+	schema := loadCRDSchema(t)
+
+	// Spec fields the operator reads and status fields written for a session
+	paths := []string{
+		"spec.prompt",
+		"spec.promptConfigMapRef.name",
+		"spec.promptConfigMapRef.key",
+		"spec.promptVars",
+		"spec.websiteURL",
+		"spec.timeout",
+		"spec.retries",
+		"spec.llmSettings.model",
+		"spec.llmSettings.temperature",
+		"spec.llmSettings.maxTokens",
+		"spec.llmSettings.apiKeySecretRef.name",
+		"spec.llmSettings.apiKeySecretRef.key",
+		"spec.resources.requests.cpu",
+		"spec.resources.requests.memory",
+		"spec.resources.limits.cpu",
+		"spec.resources.limits.memory",
+		"spec.imagePullSecrets",
+		"spec.env",
+		"spec.envFrom",
+		"spec.runnerImage",
+		"spec.serviceAccountName",
+		"spec.nodeSelector",
+		"spec.tolerations",
+		"spec.affinity",
+		"status.phase",
+		"status.message",
+		"status.startTime",
+		"status.completionTime",
+		"status.jobName",
+		"status.observedGeneration",
+		"status.failedPod",
+		"status.exitCode",
+		"status.runnerImage",
+		"status.runnerLog",
+		"status.durationSeconds",
+		"status.conditions",
+		// Written by the runner through the backend; the operator clears them on restart
+		"status.finalOutput",
+		"status.result",
+		"status.result.output",
+		"status.result.websiteURL",
+		"status.cost",
+		"status.messages",
+	}
+	for _, path := range paths {
+		if !schemaHasPath(schema, path) {
+			t.Errorf("%s is not declared in manifests/crd.yaml", path)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// drainRequeueInterval is how often Pending sessions held by a drain are rechecked.
+const drainRequeueInterval = 15 * time.Second
+
+const drainingMessage = "Operator is draining; the session will start when it resumes"
+
+// errDraining is returned by the reconcile path when a session was held
+// because the operator is draining.
+var errDraining = errors.New("operator is draining")
+
+// draining stops new jobs from being created; running jobs and their monitors
+// are unaffected. Starts from DRAIN=true and is toggled via POST /drain and
+// /undrain on the admin server.
+var draining atomic.Bool
+
+func initDrain() {
+	if os.Getenv("DRAIN") == "true" {
+		draining.Store(true)
+		slog.Info("Starting in drain mode: no new jobs will be created")
+	}
+}
+
+// startAdminServer serves /drain and /undrain on ADMIN_ADDR in the background.
+// They are unauthenticated, so the default binds to localhost only, out of
+// reach of the health port's probes and anything else on the pod network;
+// reach it with kubectl port-forward.
+func startAdminServer() {
+	addr := os.Getenv("ADMIN_ADDR")
+	if addr == "" {
+		addr = "127.0.0.1:8082"
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/drain", drainHandler(true))
+	mux.HandleFunc("/undrain", drainHandler(false))
+
+	go func() {
+		slog.Info("Serving admin endpoints", "addr", addr)
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			slog.Error("Admin server stopped", "err", err)
+		}
+	}()
+}
+
+func drainHandler(drain bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if draining.Swap(drain) != drain {
+			if drain {
+				slog.Info("Draining: no new jobs will be created until /undrain")
+			} else {
+				slog.Info("Drain lifted: resuming job creation")
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "draining=%t\n", drain)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// useDraining sets the drain state for the duration of the test.
+func useDraining(t *testing.T, drain bool) {
+	t.Helper()
+	prev := draining.Swap(drain)
+	t.Cleanup(func() { draining.Store(prev) })
+}
+
+func TestDrainHandler(t *testing.T) {
+	useDraining(t, false)
+
+	tests := []struct {
+		method, path string
+		code         int
+		wantDraining bool
+	}{
+		{method: http.MethodGet, path: "/drain", code: http.StatusMethodNotAllowed},
+		{method: http.MethodPost, path: "/drain", code: http.StatusOK, wantDraining: true},
+		{method: http.MethodPost, path: "/drain", code: http.StatusOK, wantDraining: true},
+		{method: http.MethodPost, path: "/undrain", code: http.StatusOK},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		drainHandler(tt.path == "/drain")(rec, httptest.NewRequest(tt.method, tt.path, nil))
+		if rec.Code != tt.code {
+			t.Errorf("%s %s = %d, want %d", tt.method, tt.path, rec.Code, tt.code)
+		}
+		if draining.Load() != tt.wantDraining {
+			t.Errorf("after %s %s draining = %t, want %t", tt.method, tt.path, draining.Load(), tt.wantDraining)
+		}
+	}
+}
+
+func TestDrainHoldsPendingSession(t *testing.T) {
+	useDraining(t, true)
+	session := newSession("s1", map[string]interface{}{"prompt": "Summarize the site"}, map[string]interface{}{"phase": "Pending"})
+	_, k8s := useFakeClients(t, session)
+
+	if err := handleResearchSessionEvent(context.Background(), session); !errors.Is(err, errDraining) {
+		t.Fatalf("err = %v, want errDraining", err)
+	}
+
+	obj, err := dynamicClient.Resource(getResearchSessionResource()).Namespace("default").Get(context.Background(), "s1", v1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if message, _, _ := unstructured.NestedString(obj.Object, "status", "message"); message != drainingMessage {
+		t.Errorf("message = %q, want %q", message, drainingMessage)
+	}
+	if jobs, _ := k8s.BatchV1().Jobs("default").List(context.Background(), v1.ListOptions{}); len(jobs.Items) != 0 {
+		t.Errorf("created %d jobs while draining", len(jobs.Items))
+	}
+}
+
+func TestDrainKeepsMonitoringRunningJobs(t *testing.T) {
+	useOperatorConfig(t, 10*time.Millisecond)
+	useDraining(t, false)
+	session := newSession("s1", map[string]interface{}{
+		"prompt":     "Summarize the site",
+		"websiteURL": "https://93.184.216.34/",
+	}, map[string]interface{}{"phase": "Pending"})
+	_, k8s := useFakeClients(t, session, apiKeySecret())
+
+	if err := handleResearchSessionEvent(context.Background(), session); err != nil {
+		t.Fatal(err)
+	}
+	if !hasMonitor("default", "s1-job") {
+		t.Fatal("no monitor started for s1-job")
+	}
+
+	// Drain, then let the running job finish
+	draining.Store(true)
+	job, err := k8s.BatchV1().Jobs("default").Get(context.Background(), "s1-job", v1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	job.Status.Succeeded = 1
+	if _, err := k8s.BatchV1().Jobs("default").UpdateStatus(context.Background(), job, v1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if phase := waitForPhase(t, "s1", "Completed"); phase != "Completed" {
+		t.Errorf("phase = %q, want Completed while draining", phase)
+	}
+}
+
+// waitForPhase polls the session until it reaches phase or a few seconds
+// pass, returning the last phase seen.
+func waitForPhase(t *testing.T, name, phase string) string {
+	t.Helper()
+	var current string
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		obj, err := dynamicClient.Resource(getResearchSessionResource()).Namespace("default").Get(context.Background(), name, v1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if current, _, _ = unstructured.NestedString(obj.Object, "status", "phase"); current == phase {
+			break
+		}
+	}
+	return current
+}
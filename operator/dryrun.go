@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// dryRun makes the operator log the writes it would make (jobs, ConfigMaps,
+// status, annotations, events) instead of sending them. Reads still go to the
+// cluster, so it can be pointed at production to see what it would do.
+var dryRun bool
+
+// dryRunVisited records the session generations a dry run has already
+// "started", since their status is never moved past Pending.
+var dryRunVisited sync.Map
+
+func initDryRun() {
+	dryRun = os.Getenv("DRY_RUN") == "true"
+	if dryRun {
+		slog.Info("Dry run: no changes will be made to the cluster")
+	}
+}
+
+// dryRunLabel is the value of the dry_run label on operator counters.
+func dryRunLabel() string {
+	return strconv.FormatBool(dryRun)
+}
+
+// logDryRun logs an action skipped by dry run along with the object it would
+// have sent, rendered as YAML.
+func logDryRun(action string, obj interface{}) {
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		slog.Info("[dry-run] Would "+action, "renderErr", err)
+		return
+	}
+	slog.Info("[dry-run] Would "+action, "object", string(data))
+}
+
+// dryRunFirstVisit reports whether this is the first time a dry run handles
+// this generation of the session.
+func dryRunFirstVisit(obj *unstructured.Unstructured) bool {
+	key := fmt.Sprintf("%s/%d", obj.GetUID(), obj.GetGeneration())
+	_, seen := dryRunVisited.LoadOrStore(key, struct{}{})
+	return !seen
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// runnerEnv reads spec.env, extra env vars for the runner container. Each
+// entry sets a value or reads one from a Secret or ConfigMap key. Names must be
+// unique and may not replace a variable the operator sets, given in managed.
+func runnerEnv(spec map[string]interface{}, managed []corev1.EnvVar) ([]corev1.EnvVar, error) {
+	var env []corev1.EnvVar
+	if _, err := specDecode(spec, &env, "env"); err != nil {
+		return nil, err
+	}
+
+	reserved := reservedEnv(managed)
+	seen := map[string]bool{}
+	for i, v := range env {
+		switch {
+		case v.Name == "":
+			return nil, invalidSpec("env", fmt.Sprintf("spec.env[%d].name is required", i))
+		case reserved[v.Name]:
+			return nil, invalidSpec("env", fmt.Sprintf("spec.env[%d]: %s is set by the operator and can't be overridden", i, v.Name))
+		case seen[v.Name]:
+			return nil, invalidSpec("env", fmt.Sprintf("spec.env[%d]: %s is set more than once", i, v.Name))
+		}
+		seen[v.Name] = true
+
+		if from := v.ValueFrom; from != nil {
+			if v.Value != "" {
+				return nil, invalidSpec("env", fmt.Sprintf("spec.env[%d]: set value or valueFrom, not both", i))
+			}
+			onlyKeyRefs := corev1.EnvVarSource{SecretKeyRef: from.SecretKeyRef, ConfigMapKeyRef: from.ConfigMapKeyRef}
+			if *from != onlyKeyRefs || (from.SecretKeyRef == nil) == (from.ConfigMapKeyRef == nil) {
+				return nil, invalidSpec("env", fmt.Sprintf("spec.env[%d].valueFrom must set exactly one of secretKeyRef or configMapKeyRef", i))
+			}
+		}
+	}
+	return env, nil
+}
+
+// reservedEnv returns the names of the variables the operator sets, given in
+// managed, which spec.env and spec.envFrom may not replace.
+func reservedEnv(managed []corev1.EnvVar) map[string]bool {
+	// PROMPT_FILE replaces PROMPT for large prompts, after these checks run
+	reserved := map[string]bool{"PROMPT_FILE": true}
+	for _, v := range managed {
+		reserved[v.Name] = true
+	}
+	return reserved
+}
+
+// runnerEnvFrom reads spec.envFrom, ConfigMaps and Secrets whose keys all
+// become env vars on the runner container. Each entry names exactly one of
+// them; checkEnvFrom verifies their keys once they have been read.
+func runnerEnvFrom(spec map[string]interface{}) ([]corev1.EnvFromSource, error) {
+	var envFrom []corev1.EnvFromSource
+	if _, err := specDecode(spec, &envFrom, "envFrom"); err != nil {
+		return nil, err
+	}
+	for i, from := range envFrom {
+		switch {
+		case (from.ConfigMapRef == nil) == (from.SecretRef == nil):
+			return nil, invalidSpec("envFrom", fmt.Sprintf("spec.envFrom[%d] must set exactly one of configMapRef or secretRef", i))
+		case from.ConfigMapRef != nil && from.ConfigMapRef.Name == "",
+			from.SecretRef != nil && from.SecretRef.Name == "":
+			return nil, invalidSpec("envFrom", fmt.Sprintf("spec.envFrom[%d] requires a name", i))
+		}
+	}
+	return envFrom, nil
+}
+
+// checkEnvFrom verifies that each spec.envFrom source exists, unless it is
+// optional, and that none of its keys would replace a variable the operator
+// sets, given in managed. Kubernetes would let the operator's values win, but
+// the runner would then silently ignore what the user asked for. Like
+// checkAPIKeySecret it returns a user-facing problem or an error for API
+// failures.
+func checkEnvFrom(ctx context.Context, namespace string, envFrom []corev1.EnvFromSource, managed []corev1.EnvVar) (string, error) {
+	reserved := reservedEnv(managed)
+	for i, from := range envFrom {
+		kind, name, optional := "ConfigMap", "", false
+		if ref := from.ConfigMapRef; ref != nil {
+			name, optional = ref.Name, ref.Optional != nil && *ref.Optional
+		} else {
+			kind, name, optional = "Secret", from.SecretRef.Name, from.SecretRef.Optional != nil && *from.SecretRef.Optional
+		}
+
+		keys, err := envFromKeys(ctx, namespace, kind, name)
+		if errors.IsNotFound(err) {
+			if optional {
+				continue
+			}
+			return fmt.Sprintf("spec.envFrom[%d]: %s %q not found in namespace %s", i, kind, name, namespace), nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s %s for spec.envFrom: %v", kind, name, err)
+		}
+		for _, key := range keys {
+			if reserved[from.Prefix+key] {
+				return fmt.Sprintf("spec.envFrom[%d]: %s %q sets %s, which is set by the operator and can't be overridden", i, kind, name, from.Prefix+key), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// envFromKeys returns the sorted keys of the named ConfigMap or Secret.
+func envFromKeys(ctx context.Context, namespace, kind, name string) ([]string, error) {
+	reqCtx, cancel := apiContext(ctx)
+	defer cancel()
+
+	var keys []string
+	if kind == "ConfigMap" {
+		cm, err := k8sClient.CoreV1().ConfigMaps(namespace).Get(reqCtx, name, v1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for key := range cm.Data {
+			keys = append(keys, key)
+		}
+	} else {
+		secret, err := k8sClient.CoreV1().Secrets(namespace).Get(reqCtx, name, v1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for key := range secret.Data {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
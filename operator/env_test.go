@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRunnerEnvFrom(t *testing.T) {
+	tests := []struct {
+		name    string
+		envFrom []interface{}
+		wantErr string
+	}{
+		{name: "configmap", envFrom: []interface{}{map[string]interface{}{"configMapRef": map[string]interface{}{"name": "settings"}}}},
+		{name: "secret with prefix", envFrom: []interface{}{map[string]interface{}{"secretRef": map[string]interface{}{"name": "creds"}, "prefix": "SITE_"}}},
+		{name: "neither", envFrom: []interface{}{map[string]interface{}{"prefix": "SITE_"}}, wantErr: "exactly one of configMapRef or secretRef"},
+		{
+			name:    "both",
+			envFrom: []interface{}{map[string]interface{}{"configMapRef": map[string]interface{}{"name": "a"}, "secretRef": map[string]interface{}{"name": "b"}}},
+			wantErr: "exactly one of configMapRef or secretRef",
+		},
+		{name: "no name", envFrom: []interface{}{map[string]interface{}{"secretRef": map[string]interface{}{}}}, wantErr: "requires a name"},
+		{name: "unknown field", envFrom: []interface{}{map[string]interface{}{"configMap": "settings"}}, wantErr: "unknown field"},
+	}
+	for _, tt := range tests {
+		_, err := runnerEnvFrom(map[string]interface{}{"envFrom": tt.envFrom})
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: err = %v, want %q", tt.name, err, tt.wantErr)
+		} else if reason := specErrorReason(err); reason != "InvalidEnvFrom" {
+			t.Errorf("%s: reason = %s, want InvalidEnvFrom", tt.name, reason)
+		}
+	}
+}
+
+func TestCheckEnvFrom(t *testing.T) {
+	useFakeClients(t,
+		&corev1.ConfigMap{
+			ObjectMeta: v1.ObjectMeta{Name: "settings", Namespace: "default"},
+			Data:       map[string]string{"NODE_ENV": "production"},
+		},
+		&corev1.Secret{
+			ObjectMeta: v1.ObjectMeta{Name: "creds", Namespace: "default"},
+			Data:       map[string][]byte{"API_KEY": []byte("x")},
+		},
+	)
+	managed := []corev1.EnvVar{{Name: "PROMPT"}, {Name: "ANTHROPIC_API_KEY"}}
+	optional := true
+
+	tests := []struct {
+		name    string
+		envFrom []corev1.EnvFromSource
+		problem string
+	}{
+		{
+			name: "allowed",
+			envFrom: []corev1.EnvFromSource{
+				{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "settings"}}},
+				{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "creds"}}, Prefix: "SITE_"},
+			},
+		},
+		{
+			name:    "missing",
+			envFrom: []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "absent"}}}},
+			problem: `Secret "absent" not found in namespace default`,
+		},
+		{
+			name: "missing but optional",
+			envFrom: []corev1.EnvFromSource{{ConfigMapRef: &corev1.ConfigMapEnvSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "absent"}, Optional: &optional,
+			}}},
+		},
+		{
+			// The prefix turns the secret's API_KEY into an operator-managed name
+			name:    "reserved key",
+			envFrom: []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "creds"}}, Prefix: "ANTHROPIC_"}},
+			problem: "sets ANTHROPIC_API_KEY, which is set by the operator",
+		},
+	}
+	for _, tt := range tests {
+		problem, err := checkEnvFrom(context.Background(), "default", tt.envFrom, managed)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if tt.problem == "" && problem != "" || !strings.Contains(problem, tt.problem) {
+			t.Errorf("%s: problem = %q, want %q", tt.name, problem, tt.problem)
+		}
+	}
+}
+
+func TestEnvFromReachesRunner(t *testing.T) {
+	useOperatorConfig(t, time.Hour)
+	session := newSession("s1", map[string]interface{}{
+		"prompt":     "Summarize the site",
+		"websiteURL": "https://93.184.216.34/",
+		"envFrom":    []interface{}{map[string]interface{}{"configMapRef": map[string]interface{}{"name": "settings"}}},
+	}, map[string]interface{}{"phase": "Pending"})
+	_, k8s := useFakeClients(t, session, apiKeySecret(), &corev1.ConfigMap{
+		ObjectMeta: v1.ObjectMeta{Name: "settings", Namespace: "default"},
+		Data:       map[string]string{"NODE_ENV": "production"},
+	})
+
+	if err := handleResearchSessionEvent(context.Background(), session); err != nil {
+		t.Fatal(err)
+	}
+
+	job, err := k8s.BatchV1().Jobs("default").Get(context.Background(), "s1-job", v1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if envFrom := runnerContainer(t, job).EnvFrom; len(envFrom) != 1 || envFrom[0].ConfigMapRef == nil || envFrom[0].ConfigMapRef.Name != "settings" {
+		t.Errorf("envFrom = %v, want the settings ConfigMap", envFrom)
+	}
+}
+
+func TestEnvFromMissingSourceFailsSpec(t *testing.T) {
+	useOperatorConfig(t, time.Hour)
+	session := newSession("s1", map[string]interface{}{
+		"prompt":     "Summarize the site",
+		"websiteURL": "https://93.184.216.34/",
+		"envFrom":    []interface{}{map[string]interface{}{"secretRef": map[string]interface{}{"name": "absent"}}},
+	}, map[string]interface{}{"phase": "Pending"})
+	useFakeClients(t, session, apiKeySecret())
+
+	if err := handleResearchSessionEvent(context.Background(), session); err != nil {
+		t.Fatal(err)
+	}
+	if cond := sessionCondition(t, "s1", conditionSpecValid); cond == nil || cond["status"] != "False" || cond["reason"] != "InvalidEnvFrom" {
+		t.Errorf("SpecValid = %v, want False/InvalidEnvFrom", cond)
+	}
+}
+
+// runnerContainer returns the job's claude-runner container.
+func runnerContainer(t *testing.T, job *batchv1.Job) *corev1.Container {
+	t.Helper()
+	for i := range job.Spec.Template.Spec.Containers {
+		if c := &job.Spec.Template.Spec.Containers[i]; c.Name == "claude-runner" {
+			return c
+		}
+	}
+	t.Fatal("job has no claude-runner container")
+	return nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// Event reasons emitted on ResearchSession objects
+const (
+	reasonJobCreated = "JobCreated"
+	reasonCompleted  = "Completed"
+	reasonFailed     = "Failed"
+)
+
+var eventRecorder record.EventRecorder
+
+// initEventRecorder starts publishing Events to the API server.
+func initEventRecorder() {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: k8sClient.CoreV1().Events("")})
+	eventRecorder = broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "research-operator"})
+}
+
+// recordEvent emits an Event on the ResearchSession so `kubectl describe` shows
+// a timeline of what the operator did.
+func recordEvent(obj *unstructured.Unstructured, eventType, reason, messageFmt string, args ...interface{}) {
+	ref := &corev1.ObjectReference{
+		APIVersion:      obj.GetAPIVersion(),
+		Kind:            obj.GetKind(),
+		Name:            obj.GetName(),
+		Namespace:       obj.GetNamespace(),
+		UID:             obj.GetUID(),
+		ResourceVersion: obj.GetResourceVersion(),
+	}
+	if dryRun {
+		slog.Info("[dry-run] Would record event", "namespace", ref.Namespace, "session", ref.Name,
+			"type", eventType, "reason", reason, "message", fmt.Sprintf(messageFmt, args...))
+		return
+	}
+	eventRecorder.Eventf(ref, eventType, reason, messageFmt, args...)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/tools/cache"
+)
+
+// watchErrorWindow is how long after the last watch error the operator reports
+// not ready. The reflector retries with backoff, so a persistently broken watch
+// keeps reporting errors inside this window.
+const watchErrorWindow = 60 * time.Second
+
+// watchHealth tracks whether the ResearchSession watch is connected.
+var watchHealth struct {
+	sync.Mutex
+	synced         bool
+	standby        bool
+	lastWatchError time.Time
+	lastError      error
+}
+
+func setWatchSynced(synced bool) {
+	watchHealth.Lock()
+	defer watchHealth.Unlock()
+	watchHealth.synced = synced
+}
+
+// setStandby marks a replica waiting for the leader lease. Standbys have no
+// watch by design and are reported ready so they don't block rollouts.
+func setStandby(standby bool) {
+	watchHealth.Lock()
+	defer watchHealth.Unlock()
+	watchHealth.standby = standby
+}
+
+// watchErrorHandler records watch failures before delegating to the default handler.
+func watchErrorHandler(ctx context.Context, r *cache.Reflector, err error) {
+	watchHealth.Lock()
+	watchHealth.lastWatchError = time.Now()
+	watchHealth.lastError = err
+	watchHealth.Unlock()
+
+	cache.DefaultWatchErrorHandler(ctx, r, err)
+}
+
+// readiness returns nil when the operator is actively watching ResearchSessions.
+func readiness() error {
+	watchHealth.Lock()
+	defer watchHealth.Unlock()
+
+	if watchHealth.standby {
+		return nil
+	}
+	if !watchHealth.synced {
+		return fmt.Errorf("ResearchSession watch not synced")
+	}
+	if time.Since(watchHealth.lastWatchError) < watchErrorWindow {
+		return fmt.Errorf("ResearchSession watch failing: %v", watchHealth.lastError)
+	}
+	return nil
+}
+
+// startHealthServer serves /healthz and /readyz on HEALTH_PORT (default 8081) in the background.
+func startHealthServer() {
+	port := os.Getenv("HEALTH_PORT")
+	if port == "" {
+		port = "8081"
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if err := readiness(); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "ok")
+	})
+
+	registerVersionHandler(mux)
+
+	go func() {
+		slog.Info("Serving health checks", "port", port)
+		if err := http.ListenAndServe(":"+port, mux); err != nil {
+			slog.Error("Health server stopped", "err", err)
+		}
+	}()
+}
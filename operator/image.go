@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// imageReferencePattern accepts [registry[:port]/]path[:tag][@sha256:digest]
+// with lowercase path components, which is what container runtimes will pull.
+var imageReferencePattern = regexp.MustCompile(`^(?:[a-zA-Z0-9.-]+(?::[0-9]+)?/)?` +
+	`[a-z0-9]+(?:[._-]+[a-z0-9]+)*(?:/[a-z0-9]+(?:[._-]+[a-z0-9]+)*)*` +
+	`(?::[\w][\w.-]{0,127})?(?:@sha256:[a-f0-9]{64})?$`)
+
+// runnerImage returns spec.runnerImage, or CLAUDE_RUNNER_IMAGE when it's unset.
+func runnerImage(spec map[string]interface{}) (string, error) {
+	image, found, err := specString(spec, "runnerImage")
+	if err != nil {
+		return "", err
+	}
+	if !found || image == "" {
+		return *claudeRunnerImage.Load(), nil
+	}
+	if !imageReferencePattern.MatchString(image) {
+		return "", invalidSpec("runnerImage", fmt.Sprintf("spec.runnerImage %q is not a valid image reference", image))
+	}
+	return image, nil
+}
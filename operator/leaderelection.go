@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// runWithLeaderElection blocks until this replica holds the Lease, then runs
+// run with a context that is cancelled as soon as leadership is lost.
+func runWithLeaderElection(ctx context.Context, run func(ctx context.Context)) {
+	leaseName := os.Getenv("LEADER_ELECTION_LEASE_NAME")
+	if leaseName == "" {
+		leaseName = "research-operator-leader"
+	}
+
+	// Identity must be unique per replica; the pod name is the natural choice
+	identity := os.Getenv("LEADER_ELECTION_ID")
+	if identity == "" {
+		identity = os.Getenv("POD_NAME")
+	}
+	if identity == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			fatal("Failed to determine leader election identity", "err", err)
+		}
+		identity = hostname
+	}
+
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: v1.ObjectMeta{
+			Name:      leaseName,
+			Namespace: operatorNamespace,
+		},
+		Client: k8sClient.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: identity,
+		},
+	}
+
+	slog.Info("Leader election enabled", "namespace", operatorNamespace, "lease", leaseName, "identity", identity)
+
+	leaderCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	setStandby(true)
+
+	leaderelection.RunOrDie(leaderCtx, leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   15 * time.Second,
+		RenewDeadline:   10 * time.Second,
+		RetryPeriod:     2 * time.Second,
+		ReleaseOnCancel: true,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				slog.Info("Acquired leadership", "identity", identity)
+				setStandby(false)
+				run(ctx)
+			},
+			OnStoppedLeading: func() {
+				// Stop the watch loop; the process exits once RunOrDie returns
+				slog.Warn("Lost leadership, stopping", "identity", identity)
+				cancel()
+			},
+			OnNewLeader: func(current string) {
+				if current != identity {
+					slog.Info("New leader elected", "leader", current)
+				}
+			},
+		},
+	})
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Defaults for spec.llmSettings fields that are left out, matching the CRD defaults
+const (
+	defaultLLMModel       = "claude-3-5-sonnet-20241022"
+	defaultLLMTemperature = 0.7
+	defaultLLMMaxTokens   = 4000
+)
+
+var (
+	// modelsAllowlist restricts spec.llmSettings.model; empty allows any model
+	modelsAllowlist map[string]bool
+	// maxTokensCap is the upper bound on spec.llmSettings.maxTokens
+	maxTokensCap int64
+)
+
+func initLLMSettingsLimits() {
+	for _, model := range strings.Split(os.Getenv("MODELS_ALLOWLIST"), ",") {
+		if model = strings.TrimSpace(model); model != "" {
+			if modelsAllowlist == nil {
+				modelsAllowlist = map[string]bool{}
+			}
+			modelsAllowlist[model] = true
+		}
+	}
+
+	maxTokensCap = 8000
+	if v := os.Getenv("MAX_TOKENS_CAP"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n <= 0 {
+			fatal("Invalid MAX_TOKENS_CAP: must be a positive integer", "value", v)
+		}
+		maxTokensCap = n
+	}
+}
+
+// llmConfig holds the validated model settings passed to the runner
+type llmConfig struct {
+	model       string
+	temperature float64
+	maxTokens   int64
+}
+
+// parseLLMSettings applies defaults to spec.llmSettings and validates it,
+// returning an error suitable for the session's status message.
+func parseLLMSettings(settings map[string]interface{}) (llmConfig, error) {
+	result := llmConfig{
+		model:       defaultLLMModel,
+		temperature: defaultLLMTemperature,
+		maxTokens:   defaultLLMMaxTokens,
+	}
+
+	if value, ok := settings["model"]; ok {
+		model, isString := value.(string)
+		if !isString {
+			return llmConfig{}, fmt.Errorf("llmSettings.model must be a string")
+		}
+		if model != "" {
+			result.model = model
+		}
+	}
+	if modelsAllowlist != nil && !modelsAllowlist[result.model] {
+		return llmConfig{}, fmt.Errorf("llmSettings.model %q is not allowed", result.model)
+	}
+
+	if value, ok := settings["temperature"]; ok {
+		temperature, isNumber := asFloat64(value)
+		if !isNumber {
+			return llmConfig{}, fmt.Errorf("llmSettings.temperature must be a number")
+		}
+		if temperature < 0 || temperature > 2 {
+			return llmConfig{}, fmt.Errorf("llmSettings.temperature must be between 0 and 2, got %g", temperature)
+		}
+		result.temperature = temperature
+	}
+
+	if value, ok := settings["maxTokens"]; ok {
+		maxTokens, isNumber := asFloat64(value)
+		if !isNumber || maxTokens != math.Trunc(maxTokens) {
+			return llmConfig{}, fmt.Errorf("llmSettings.maxTokens must be an integer")
+		}
+		if maxTokens <= 0 || maxTokens > float64(maxTokensCap) {
+			return llmConfig{}, fmt.Errorf("llmSettings.maxTokens must be between 1 and %d, got %g", maxTokensCap, maxTokens)
+		}
+		result.maxTokens = int64(maxTokens)
+	}
+
+	return result, nil
+}
+
+// asFloat64 reads a JSON number from an unstructured object, which decodes
+// as int64 or float64 depending on how it was written.
+func asFloat64(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"sync/atomic"
+)
+
+// logLevel is the minimum level logged; CONFIG_DIR can change it at runtime
+var logLevel slog.LevelVar
+
+// initLogging installs the slog handler selected by LOG_FORMAT (text or
+// json) at the level from LOG_LEVEL.
+func initLogging() {
+	level, err := parseLogLevel(os.Getenv("LOG_LEVEL"))
+	if err != nil {
+		fatal("Invalid LOG_LEVEL", "err", err)
+	}
+	logLevel.Set(level)
+	opts := &slog.HandlerOptions{Level: &logLevel}
+
+	var handler slog.Handler
+	switch format := os.Getenv("LOG_FORMAT"); format {
+	case "", "text":
+		handler = slog.NewTextHandler(os.Stderr, opts)
+	case "json":
+		handler = slog.NewJSONHandler(os.Stderr, opts)
+	default:
+		fatal("Invalid LOG_FORMAT: must be text or json", "value", format)
+	}
+	slog.SetDefault(slog.New(handler))
+}
+
+// fatal logs a startup error and exits, for settings the operator can't run with.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// parseLogLevel reads a LOG_LEVEL value, defaulting to info when empty.
+func parseLogLevel(v string) (slog.Level, error) {
+	level := slog.LevelInfo
+	if v != "" {
+		if err := level.UnmarshalText([]byte(v)); err != nil {
+			return 0, fmt.Errorf("%q must be debug, info, warn or error", v)
+		}
+	}
+	return level, nil
+}
+
+// reconcileSeq numbers reconciles so one pass over a session can be followed
+// through the logs.
+var reconcileSeq atomic.Uint64
+
+type loggerKey struct{}
+
+// withReconcileLogger returns a context carrying a logger tagged with the
+// session and a fresh reconcile ID.
+func withReconcileLogger(ctx context.Context, namespace, name string) context.Context {
+	logger := slog.With(
+		"reconcile", strconv.FormatUint(reconcileSeq.Add(1), 10),
+		"namespace", namespace,
+		"session", name,
+	)
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// loggerFrom returns the logger stored by withReconcileLogger, or the default
+// logger outside a reconcile.
+func loggerFrom(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
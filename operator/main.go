@@ -2,10 +2,20 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
+	"unicode/utf8"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -13,47 +23,356 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// restartAnnotation, when set to "true", makes the operator delete the current
+// job and rerun the session from Pending.
+const restartAnnotation = "research.example.com/restart"
+
+// Secret holding the runner's Anthropic API key when a session doesn't set
+// spec.llmSettings.apiKeySecretRef
+const (
+	defaultAPIKeySecretName = "claude-research-secrets"
+	defaultAPIKeySecretKey  = "anthropic-api-key"
 )
 
 var (
-	k8sClient         *kubernetes.Clientset
-	dynamicClient     dynamic.Interface
-	namespace         string
-	claudeRunnerImage string
+	k8sClient          kubernetes.Interface
+	dynamicClient      dynamic.Interface
+	operatorNamespace  string
+	watchAllNamespaces bool
+	claudeRunnerImage  atomic.Pointer[string]
+	maxPromptBytes     int
+	promptTruncate     bool
+	promptFileBytes    int
+	apiTimeout         time.Duration
+	maxSessionTimeout  int64
+	jobTTLSeconds      int32
+	resyncPeriod       time.Duration
+	jobPollInterval    atomic.Int64 // time.Duration
+	imagePullSecrets   []string
+	runnerSA           string
 )
 
+// defaultSessionTimeout is used when a ResearchSession doesn't set spec.timeout.
+const defaultSessionTimeout = 300
+
+// defaultSessionRetries is the job BackoffLimit when spec.retries is unset
+const defaultSessionRetries = 3
+
+// defaultJobBackoffLimit is what Kubernetes applies when BackoffLimit is unset
+const defaultJobBackoffLimit = 6
+
 func main() {
+	printVersion := flag.Bool("version", false, "print the operator version and exit")
+	flag.Parse()
+	if *printVersion {
+		fmt.Println(currentVersion())
+		return
+	}
+
+	initLogging()
+	slog.Info("Starting", "version", currentVersion())
+
 	// Initialize Kubernetes clients
 	if err := initK8sClients(); err != nil {
-		log.Fatalf("Failed to initialize Kubernetes clients: %v", err)
+		fatal("Failed to initialize Kubernetes clients", "err", err)
 	}
 
 	// Get namespace from environment or use default
-	namespace = os.Getenv("NAMESPACE")
-	if namespace == "" {
-		namespace = "default"
+	operatorNamespace = os.Getenv("NAMESPACE")
+	if operatorNamespace == "" {
+		operatorNamespace = "default"
 	}
 
+	// Watch ResearchSessions cluster-wide instead of only in NAMESPACE; each
+	// session's job runs in the session's own namespace
+	watchAllNamespaces = os.Getenv("WATCH_ALL_NAMESPACES") == "true"
+
 	// Get claude-runner image from environment or use default
-	claudeRunnerImage = os.Getenv("CLAUDE_RUNNER_IMAGE")
-	if claudeRunnerImage == "" {
-		claudeRunnerImage = "quay.io/gkrumbach07/claude-runner:latest"
+	image := parseRunnerImage(os.Getenv("CLAUDE_RUNNER_IMAGE"))
+	claudeRunnerImage.Store(&image)
+
+	// Pull secrets added to every runner pod, for images in private registries
+	for _, secret := range strings.Split(os.Getenv("IMAGE_PULL_SECRETS"), ",") {
+		if secret = strings.TrimSpace(secret); secret != "" {
+			imagePullSecrets = append(imagePullSecrets, secret)
+		}
+	}
+
+	// ServiceAccount for runner pods, e.g. for workload identity; empty keeps the namespace default
+	runnerSA = os.Getenv("RUNNER_SERVICE_ACCOUNT")
+
+	// Prompts over MAX_PROMPT_BYTES are rejected, or truncated when PROMPT_TRUNCATE=true
+	maxPromptBytes = 128 * 1024
+	if v := os.Getenv("MAX_PROMPT_BYTES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			fatal("Invalid MAX_PROMPT_BYTES: must be a positive integer", "value", v)
+		}
+		maxPromptBytes = n
+	}
+	promptTruncate = os.Getenv("PROMPT_TRUNCATE") == "true"
+
+	// Prompts over PROMPT_FILE_THRESHOLD_BYTES are mounted as a file instead of an env var
+	promptFileBytes = 32 * 1024
+	if v := os.Getenv("PROMPT_FILE_THRESHOLD_BYTES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			fatal("Invalid PROMPT_FILE_THRESHOLD_BYTES: must be a positive integer", "value", v)
+		}
+		promptFileBytes = n
+	}
+
+	// Timeout applied to each Kubernetes API call
+	apiTimeout = 30 * time.Second
+	if v := os.Getenv("API_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			fatal("Invalid API_TIMEOUT: must be a positive duration like 30s", "value", v)
+		}
+		apiTimeout = d
+	}
+
+	// Upper bound on spec.timeout, and so on each job's ActiveDeadlineSeconds
+	maxSessionTimeout = 1800
+	if v := os.Getenv("MAX_SESSION_TIMEOUT"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n <= 0 {
+			fatal("Invalid MAX_SESSION_TIMEOUT: must be a positive number of seconds", "value", v)
+		}
+		maxSessionTimeout = n
+	}
+
+	// How long finished runner jobs and their pods are kept before Kubernetes deletes them
+	jobTTLSeconds = 3600
+	if v := os.Getenv("JOB_TTL"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || n < 0 {
+			fatal("Invalid JOB_TTL: must be a non-negative number of seconds", "value", v)
+		}
+		jobTTLSeconds = int32(n)
+	}
+
+	// Lines of runner output mirrored into status.runnerLog while a job runs; 0 disables
+	runnerLogTailLines = 20
+	if v := os.Getenv("RUNNER_LOG_TAIL_LINES"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			fatal("Invalid RUNNER_LOG_TAIL_LINES: must be a non-negative integer", "value", v)
+		}
+		runnerLogTailLines = n
+	}
+
+	// How often every ResearchSession is re-reconciled to heal missed events; 0 disables
+	resyncPeriod = 10 * time.Minute
+	if v := os.Getenv("RESYNC_PERIOD"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			fatal("Invalid RESYNC_PERIOD: must be a duration like 10m, or 0 to disable", "value", v)
+		}
+		resyncPeriod = d
+	}
+
+	// How often monitors check their job when the job watch is quiet or unavailable
+	pollInterval, err := parseJobPollInterval(os.Getenv("JOB_POLL_INTERVAL"))
+	if err != nil {
+		fatal("Invalid JOB_POLL_INTERVAL", "err", err)
+	}
+	jobPollInterval.Store(int64(pollInterval))
+
+	if watchAllNamespaces {
+		slog.Info("Research Session Operator starting, watching all namespaces")
+	} else {
+		slog.Info("Research Session Operator starting", "namespace", operatorNamespace)
+	}
+	slog.Info("Using claude-runner image", "image", *claudeRunnerImage.Load())
+	slog.Info("Maximum prompt size", "bytes", maxPromptBytes, "truncate", promptTruncate)
+
+	initDryRun()
+	initDrain()
+	initConcurrencyLimit()
+	initLLMSettingsLimits()
+	initURLPolicy()
+	initRunnerScheduling()
+	initEventRecorder()
+	initNotifications()
+	registerMetrics()
+	startMetricsServer()
+	startHealthServer()
+	startAdminServer()
+
+	// SIGINT/SIGTERM cancel ctx, which stops the watch and job monitors
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	initRuntimeConfig(ctx)
+
+	if os.Getenv("ENABLE_LEADER_ELECTION") == "true" && dryRun {
+		// Holding the Lease is a cluster write, and it would keep a real
+		// operator from leading, so a dry run never takes part in the election
+		slog.Info("Dry run: skipping leader election")
+		watchResearchSessions(ctx)
+	} else if os.Getenv("ENABLE_LEADER_ELECTION") == "true" {
+		// With leader election only the lease holder watches; standbys block until they acquire it
+		runWithLeaderElection(ctx, watchResearchSessions)
+	} else {
+		// Watch ResearchSession resources
+		watchResearchSessions(ctx)
 	}
 
-	log.Printf("Research Session Operator starting in namespace: %s", namespace)
-	log.Printf("Using claude-runner image: %s", claudeRunnerImage)
+	slog.Info("Shutting down, waiting for job monitors to finish")
+	stopMonitors()
+	if waitForMonitors(shutdownTimeout) {
+		slog.Info("Job monitors finished")
+	} else {
+		slog.Warn("Timed out waiting for job monitors", "timeout", shutdownTimeout)
+	}
+	slog.Info("Research Session Operator stopped")
+}
 
-	// Start watching ResearchSession resources
-	go watchResearchSessions()
+// shutdownTimeout bounds how long shutdown waits for in-flight job monitors,
+// staying under the default 30s termination grace period.
+const shutdownTimeout = 20 * time.Second
 
-	// Keep the operator running
-	select {}
+// monitorWG tracks running monitorJob goroutines so shutdown can wait for them.
+var monitorWG sync.WaitGroup
+
+// monitorHandle identifies one running monitor so a replaced monitor's exit
+// doesn't unregister its successor.
+type monitorHandle struct {
+	cancel context.CancelFunc
+}
+
+// monitors holds the active monitor per namespace/job name, so each job has at
+// most one goroutine polling it and writing its session's status.
+var (
+	monitorsMu sync.Mutex
+	monitors   = map[string]*monitorHandle{}
+)
+
+func monitorKey(namespace, jobName string) string {
+	return namespace + "/" + jobName
+}
+
+// startMonitor runs monitorJob in the background, tracked by monitorWG. Any
+// monitor already running for jobName is cancelled and replaced.
+func startMonitor(ctx context.Context, namespace, jobName, sessionName string) {
+	monitorCtx, cancel := context.WithCancel(ctx)
+	handle := &monitorHandle{cancel: cancel}
+	key := monitorKey(namespace, jobName)
+
+	monitorsMu.Lock()
+	if existing, ok := monitors[key]; ok {
+		slog.Info("Replacing existing monitor", "namespace", namespace, "job", jobName)
+		existing.cancel()
+	}
+	monitors[key] = handle
+	monitorsMu.Unlock()
+
+	monitorWG.Add(1)
+	go func() {
+		defer monitorWG.Done()
+		defer func() {
+			monitorsMu.Lock()
+			if monitors[key] == handle {
+				delete(monitors, key)
+			}
+			monitorsMu.Unlock()
+			cancel()
+		}()
+		monitorJob(monitorCtx, namespace, jobName, sessionName)
+	}()
+}
+
+// hasMonitor reports whether a monitor is running for jobName.
+func hasMonitor(namespace, jobName string) bool {
+	key := monitorKey(namespace, jobName)
+	monitorsMu.Lock()
+	defer monitorsMu.Unlock()
+	_, ok := monitors[key]
+	return ok
+}
+
+// stopMonitor cancels the monitor for jobName, if one is running.
+func stopMonitor(namespace, jobName string) {
+	key := monitorKey(namespace, jobName)
+	monitorsMu.Lock()
+	defer monitorsMu.Unlock()
+	if handle, ok := monitors[key]; ok {
+		handle.cancel()
+		delete(monitors, key)
+	}
+}
+
+// stopMonitors cancels every running monitor.
+func stopMonitors() {
+	monitorsMu.Lock()
+	defer monitorsMu.Unlock()
+	for key, handle := range monitors {
+		handle.cancel()
+		delete(monitors, key)
+	}
+}
+
+// waitForMonitors waits up to timeout for all job monitors to return,
+// reporting whether they did.
+func waitForMonitors(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		monitorWG.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// apiContext bounds a single Kubernetes API call to apiTimeout. Writes pass
+// context.Background() as parent so they still complete during shutdown.
+func apiContext(parent context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(parent, apiTimeout)
+}
+
+// watchJob opens a watch on a single job, returning nil if the watch can't
+// be started so the caller falls back to polling.
+func watchJob(ctx context.Context, namespace, jobName string) watch.Interface {
+	w, err := k8sClient.BatchV1().Jobs(namespace).Watch(ctx, v1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", jobName).String(),
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			loggerFrom(ctx).Warn("Failed to watch job, polling instead", "job", jobName, "err", err)
+		}
+		return nil
+	}
+	return w
+}
+
+// sleepCtx sleeps for d, returning false early if ctx is cancelled.
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
 }
 
 func initK8sClients() error {
@@ -88,6 +407,14 @@ func initK8sClients() error {
 	return nil
 }
 
+// watchNamespace is the namespace the informer lists and watches.
+func watchNamespace() string {
+	if watchAllNamespaces {
+		return v1.NamespaceAll
+	}
+	return operatorNamespace
+}
+
 func getResearchSessionResource() schema.GroupVersionResource {
 	return schema.GroupVersionResource{
 		Group:    "research.example.com",
@@ -96,58 +423,166 @@ func getResearchSessionResource() schema.GroupVersionResource {
 	}
 }
 
-func watchResearchSessions() {
+// watchResearchSessions runs a shared informer for ResearchSessions and a
+// reconcile worker fed by a rate-limited work queue until ctx is cancelled.
+// Failed reconciles are requeued with backoff instead of being dropped.
+func watchResearchSessions(ctx context.Context) {
 	gvr := getResearchSessionResource()
 
-	for {
-		watcher, err := dynamicClient.Resource(gvr).Namespace(namespace).Watch(context.TODO(), v1.ListOptions{})
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(dynamicClient, resyncPeriod, watchNamespace(), nil)
+	informer := factory.ForResource(gvr).Informer()
+
+	queue := workqueue.NewTypedRateLimitingQueueWithConfig(
+		workqueue.DefaultTypedControllerRateLimiter[string](),
+		workqueue.TypedRateLimitingQueueConfig[string]{
+			Name:            "researchsessions",
+			MetricsProvider: queueMetricsProvider{},
+		},
+	)
+	defer queue.ShutDown()
+
+	enqueue := func(obj interface{}) {
+		key, err := cache.MetaNamespaceKeyFunc(obj)
 		if err != nil {
-			log.Printf("Failed to create watcher: %v", err)
-			time.Sleep(5 * time.Second)
-			continue
+			slog.Error("Failed to get key for ResearchSession", "err", err)
+			return
 		}
+		queue.Add(key)
+	}
 
-		log.Println("Watching for ResearchSession events...")
+	if err := informer.SetWatchErrorHandlerWithContext(watchErrorHandler); err != nil {
+		slog.Error("Failed to set ResearchSession watch error handler", "err", err)
+	}
+	defer setWatchSynced(false)
 
-		for event := range watcher.ResultChan() {
-			switch event.Type {
-			case watch.Added, watch.Modified:
-				obj := event.Object.(*unstructured.Unstructured)
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			// The initial list is enqueued oldest-first once the cache has synced
+			if !isInInitialList {
+				enqueue(obj)
+			}
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			enqueue(newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj); err == nil {
+				slog.Info("ResearchSession deleted", "key", key)
+			}
+		},
+	})
+	if err != nil {
+		slog.Error("Failed to add ResearchSession event handler", "err", err)
+		return
+	}
 
-				// Add small delay to avoid race conditions with rapid create/delete cycles
-				time.Sleep(100 * time.Millisecond)
+	factory.Start(ctx.Done())
+	if !cache.WaitForNamedCacheSync("researchsessions", ctx.Done(), informer.HasSynced) {
+		slog.Warn("Stopped before the ResearchSession cache synced")
+		return
+	}
+	setWatchSynced(true)
 
-				if err := handleResearchSessionEvent(obj); err != nil {
-					log.Printf("Error handling ResearchSession event: %v", err)
-				}
-			case watch.Deleted:
-				obj := event.Object.(*unstructured.Unstructured)
-				sessionName := obj.GetName()
-				log.Printf("ResearchSession %s deleted", sessionName)
-
-				// Cancel any ongoing job monitoring for this session
-				// (We could implement this with a context cancellation if needed)
-			case watch.Error:
-				obj := event.Object.(*unstructured.Unstructured)
-				log.Printf("Watch error for ResearchSession: %v", obj)
-			}
-		}
+	// Process sessions that already existed oldest-first rather than in
+	// whatever order the initial list returned them
+	var existing []unstructured.Unstructured
+	for _, obj := range informer.GetStore().List() {
+		existing = append(existing, *obj.(*unstructured.Unstructured))
+	}
+	sortByCreation(existing)
+	for i := range existing {
+		enqueue(&existing[i])
+	}
 
-		log.Println("Watch channel closed, restarting...")
-		watcher.Stop()
-		time.Sleep(2 * time.Second)
+	slog.Info("Watching for ResearchSession events")
+
+	go func() {
+		<-ctx.Done()
+		queue.ShutDown()
+	}()
+
+	for processNextItem(ctx, queue, informer.GetStore()) {
 	}
+
+	slog.Info("Stopped watching ResearchSessions")
 }
 
-func handleResearchSessionEvent(obj *unstructured.Unstructured) error {
+// processNextItem reconciles one key from the queue, returning false once the
+// queue has been shut down.
+func processNextItem(ctx context.Context, queue workqueue.TypedRateLimitingInterface[string], store cache.Store) bool {
+	key, shutdown := queue.Get()
+	if shutdown {
+		return false
+	}
+	defer queue.Done(key)
+
+	item, exists, err := store.GetByKey(key)
+	if err != nil {
+		slog.Error("Failed to get ResearchSession from cache", "key", key, "err", err)
+		queue.AddRateLimited(key)
+		return true
+	}
+	if !exists {
+		// Deleted since it was enqueued
+		queue.Forget(key)
+		return true
+	}
+
+	obj := item.(*unstructured.Unstructured)
+	reconcileCtx := withReconcileLogger(ctx, obj.GetNamespace(), obj.GetName())
+	start := time.Now()
+	err = handleResearchSessionEvent(reconcileCtx, obj)
+	elapsed := time.Since(start).Seconds()
+	if err != nil {
+		reconcileRequeues.Inc()
+		if err == errDraining {
+			reconcileDuration.WithLabelValues("requeue").Observe(elapsed)
+			queue.Forget(key)
+			queue.AddAfter(key, drainRequeueInterval)
+			return true
+		}
+		if err == errQueued {
+			reconcileDuration.WithLabelValues("requeue").Observe(elapsed)
+			queue.Forget(key)
+			queue.AddAfter(key, queuedRequeueInterval)
+			return true
+		}
+		reconcileDuration.WithLabelValues("error").Observe(elapsed)
+		loggerFrom(reconcileCtx).Error("Error handling ResearchSession, requeuing", "err", err)
+		queue.AddRateLimited(key)
+		return true
+	}
+
+	reconcileDuration.WithLabelValues("success").Observe(elapsed)
+	queue.Forget(key)
+	return true
+}
+
+// sortByCreation orders sessions by creation timestamp, then name, so
+// processing order is stable across restarts.
+func sortByCreation(items []unstructured.Unstructured) {
+	sort.SliceStable(items, func(i, j int) bool {
+		ti, tj := items[i].GetCreationTimestamp(), items[j].GetCreationTimestamp()
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		return items[i].GetName() < items[j].GetName()
+	})
+}
+
+func handleResearchSessionEvent(ctx context.Context, obj *unstructured.Unstructured) error {
+	logger := loggerFrom(ctx)
 	name := obj.GetName()
+	namespace := obj.GetNamespace()
 
 	// Verify the resource still exists before processing
 	gvr := getResearchSessionResource()
-	currentObj, err := dynamicClient.Resource(gvr).Namespace(namespace).Get(context.TODO(), name, v1.GetOptions{})
+	reqCtx, cancel := apiContext(ctx)
+	currentObj, err := dynamicClient.Resource(gvr).Namespace(namespace).Get(reqCtx, name, v1.GetOptions{})
+	cancel()
 	if err != nil {
 		if errors.IsNotFound(err) {
-			log.Printf("ResearchSession %s no longer exists, skipping processing", name)
+			logger.Info("ResearchSession no longer exists, skipping processing")
 			return nil
 		}
 		return fmt.Errorf("failed to verify ResearchSession %s exists: %v", name, err)
@@ -157,33 +592,219 @@ func handleResearchSessionEvent(obj *unstructured.Unstructured) error {
 	status, _, _ := unstructured.NestedMap(currentObj.Object, "status")
 	phase, _, _ := unstructured.NestedString(status, "phase")
 
-	log.Printf("Processing ResearchSession %s with phase %s", name, phase)
+	logger.Debug("Processing ResearchSession", "phase", phase)
 
-	// Only process if status is Pending
-	if phase != "Pending" {
+	labelPhase := phase
+	if labelPhase == "" {
+		labelPhase = "Pending"
+	}
+	if err := syncPhaseLabel(currentObj, labelPhase); err != nil && !errors.IsNotFound(err) {
+		logger.Error("Failed to update phase label", "err", err)
+	}
+
+	// A restart request tears down the current run and resets the session to Pending;
+	// the resulting status update re-enters this handler to create a fresh job
+	if currentObj.GetAnnotations()[restartAnnotation] == "true" {
+		return restartResearchSession(ctx, currentObj, "Restart requested")
+	}
+
+	// A Running session must have a job and a monitor; either can go missing
+	// across operator restarts or manual cleanup, and the resync catches it
+	if phase == "Running" {
+		return reconcileRunningSession(ctx, currentObj)
+	}
+
+	// A Failed session whose spec was edited since it was processed gets another
+	// run; sessions that never recorded observedGeneration are left alone
+	if phase == "Failed" {
+		if observed, found, _ := unstructured.NestedInt64(status, "observedGeneration"); found && observed != currentObj.GetGeneration() {
+			return restartResearchSession(ctx, currentObj, "Spec changed, retrying")
+		}
+		return nil
+	}
+
+	// Only process if status is Pending; sessions created without a status
+	// (e.g. with kubectl, since the API server drops status on create) count as Pending
+	if phase != "Pending" && phase != "" {
 		return nil
 	}
 
+	// Hold new work while draining; the worker rechecks it periodically
+	if draining.Load() {
+		if message, _, _ := unstructured.NestedString(status, "message"); message != drainingMessage {
+			if err := updateResearchSessionStatus(namespace, name, map[string]interface{}{"message": drainingMessage}); err != nil {
+				logger.Error("Failed to mark ResearchSession as held by drain", "err", err)
+			}
+		}
+		return errDraining
+	}
+
 	// Create a Kubernetes Job for this ResearchSession
 	jobName := fmt.Sprintf("%s-job", name)
 
 	// Check if job already exists
-	_, err = k8sClient.BatchV1().Jobs(namespace).Get(context.TODO(), jobName, v1.GetOptions{})
+	reqCtx, cancel = apiContext(ctx)
+	_, err = k8sClient.BatchV1().Jobs(namespace).Get(reqCtx, jobName, v1.GetOptions{})
+	cancel()
 	if err == nil {
-		log.Printf("Job %s already exists for ResearchSession %s", jobName, name)
+		logger.Debug("Job already exists", "job", jobName)
 		return nil
 	}
 
+	// Hold new work while the concurrent job limit is reached
+	if full, err := atJobLimit(ctx); err != nil {
+		return err
+	} else if full {
+		if message, _, _ := unstructured.NestedString(status, "message"); message != queuedMessage {
+			if err := updateResearchSessionStatus(namespace, name, map[string]interface{}{"message": queuedMessage}); err != nil {
+				logger.Error("Failed to mark ResearchSession as queued", "err", err)
+			}
+		}
+		return errQueued
+	}
+
 	// Extract spec information from the fresh object
-	spec, _, _ := unstructured.NestedMap(currentObj.Object, "spec")
-	prompt, _, _ := unstructured.NestedString(spec, "prompt")
-	websiteURL, _, _ := unstructured.NestedString(spec, "websiteURL")
-	timeout, _, _ := unstructured.NestedInt64(spec, "timeout")
+	spec, _, err := unstructured.NestedMap(currentObj.Object, "spec")
+	if err != nil {
+		return failInvalidSpec(ctx, currentObj, fmt.Errorf("spec must be an object"))
+	}
+	websiteURL, _, err := specString(spec, "websiteURL")
+	if err != nil {
+		return failInvalidSpec(ctx, currentObj, err)
+	}
+	timeout, _, err := specInt64(spec, "timeout")
+	if err != nil {
+		return failInvalidSpec(ctx, currentObj, err)
+	}
+
+	// spec.timeout becomes the job's deadline so Kubernetes kills overrunning runs
+	if timeout <= 0 {
+		timeout = defaultSessionTimeout
+	}
+	if timeout > maxSessionTimeout {
+		logger.Warn("Capping timeout", "timeout", timeout, "maxTimeout", maxSessionTimeout)
+		timeout = maxSessionTimeout
+	}
+
+	// spec.retries becomes the job's BackoffLimit; 0 makes the first failure final
+	retries := int64(defaultSessionRetries)
+	if value, found, err := specInt64(spec, "retries"); err != nil {
+		return failInvalidSpec(ctx, currentObj, err)
+	} else if found {
+		if value < 0 {
+			return rejectSpec(currentObj, invalidSpec("retries", fmt.Sprintf("spec.retries must not be negative, got %d", value)))
+		}
+		retries = value
+	}
+
+	llmSettings, _, err := specMap(spec, "llmSettings")
+	if err != nil {
+		return failInvalidSpec(ctx, currentObj, err)
+	}
+	llm, err := parseLLMSettings(llmSettings)
+	if err != nil {
+		logger.Warn("Invalid LLM settings", "phase", "Failed", "err", err)
+		return rejectSpec(currentObj, invalidSpec("llmSettings", fmt.Sprintf("Invalid LLM settings: %v", err)))
+	}
+
+	// The API key comes from a Secret in the session's namespace; without an
+	// explicit reference the shared claude-research-secrets is used
+	apiKeySecretName, apiKeySecretKey := defaultAPIKeySecretName, defaultAPIKeySecretKey
+	if _, found, err := specMap(spec, "llmSettings", "apiKeySecretRef"); err != nil {
+		return failInvalidSpec(ctx, currentObj, err)
+	} else if found {
+		secretName, _, err := specString(spec, "llmSettings", "apiKeySecretRef", "name")
+		if err != nil {
+			return failInvalidSpec(ctx, currentObj, err)
+		}
+		secretKey, _, err := specString(spec, "llmSettings", "apiKeySecretRef", "key")
+		if err != nil {
+			return failInvalidSpec(ctx, currentObj, err)
+		}
+		if secretName == "" || secretKey == "" {
+			return rejectSpec(currentObj, invalidSpec("llmSettings", "llmSettings.apiKeySecretRef requires both name and key"))
+		}
+		apiKeySecretName, apiKeySecretKey = secretName, secretKey
+	}
+	if problem, err := checkAPIKeySecret(ctx, namespace, apiKeySecretName, apiKeySecretKey); err != nil {
+		return fmt.Errorf("failed to read API key secret %s: %v", apiKeySecretName, err)
+	} else if problem != "" {
+		logger.Warn("API key secret is unusable", "phase", "Failed", "problem", problem)
+		return failSession(currentObj, problem)
+	}
+
+	prompt, problem, err := resolvePrompt(ctx, namespace, spec)
+	if err != nil {
+		return err
+	}
+	if problem != "" {
+		logger.Warn("Invalid prompt", "phase", "Failed", "problem", problem)
+		return rejectSpec(currentObj, invalidSpec("prompt", problem))
+	}
+
+	// The runner browses wherever websiteURL points, so keep it off internal services
+	if problem, err := checkWebsiteURL(ctx, websiteURL); err != nil {
+		return err
+	} else if problem != "" {
+		logger.Warn("Rejecting websiteURL", "phase", "Failed", "problem", problem)
+		return rejectSpec(currentObj, invalidSpec("websiteURL", problem))
+	}
+
+	// Cap the prompt size. Prompts too big for an env var are mounted from a
+	// ConfigMap below, and a ConfigMap is limited to 1MiB
+	if len(prompt) > maxPromptBytes {
+		if !promptTruncate {
+			logger.Warn("Rejecting oversized prompt", "phase", "Failed", "bytes", len(prompt), "limit", maxPromptBytes)
+			return rejectSpec(currentObj, invalidSpec("prompt", fmt.Sprintf("Prompt is %d bytes, which exceeds the %d byte limit", len(prompt), maxPromptBytes)))
+		}
+		logger.Warn("Truncating oversized prompt", "bytes", len(prompt), "limit", maxPromptBytes)
+		prompt = truncateUTF8(prompt, maxPromptBytes)
+	}
 
-	llmSettings, _, _ := unstructured.NestedMap(spec, "llmSettings")
-	model, _, _ := unstructured.NestedString(llmSettings, "model")
-	temperature, _, _ := unstructured.NestedFloat64(llmSettings, "temperature")
-	maxTokens, _, _ := unstructured.NestedInt64(llmSettings, "maxTokens")
+	resourceRequests, _, err := specMap(spec, "resources", "requests")
+	if err != nil {
+		return failInvalidSpec(ctx, currentObj, err)
+	}
+	resourceLimits, _, err := specMap(spec, "resources", "limits")
+	if err != nil {
+		return failInvalidSpec(ctx, currentObj, err)
+	}
+	resources, err := runnerResources(quantityStrings(resourceRequests), quantityStrings(resourceLimits))
+	if err != nil {
+		logger.Warn("Invalid resources", "phase", "Failed", "err", err)
+		return rejectSpec(currentObj, invalidSpec("resources", fmt.Sprintf("Invalid resources: %v", err)))
+	}
+
+	pullSecrets, err := runnerPullSecrets(spec)
+	if err != nil {
+		return failInvalidSpec(ctx, currentObj, err)
+	}
+
+	image, err := runnerImage(spec)
+	if err != nil {
+		return failInvalidSpec(ctx, currentObj, err)
+	}
+
+	scheduling, err := runnerSchedulingFor(spec)
+	if err != nil {
+		return failInvalidSpec(ctx, currentObj, err)
+	}
+
+	serviceAccount, _, err := specString(spec, "serviceAccountName")
+	if err != nil {
+		return failInvalidSpec(ctx, currentObj, err)
+	}
+	if serviceAccount == "" {
+		serviceAccount = runnerSA
+	}
+	if serviceAccount != "" {
+		if problem, err := checkServiceAccount(ctx, namespace, serviceAccount); err != nil {
+			return fmt.Errorf("failed to read ServiceAccount %s: %v", serviceAccount, err)
+		} else if problem != "" {
+			logger.Warn("Runner ServiceAccount is unusable", "phase", "Failed", "problem", problem)
+			return failSession(currentObj, problem)
+		}
+	}
 
 	// Create the Job
 	job := &batchv1.Job{
@@ -194,21 +815,23 @@ func handleResearchSessionEvent(obj *unstructured.Unstructured) error {
 				"research-session": name,
 				"app":              "claude-runner",
 			},
+			// Deleting the ResearchSession cascades to the job and its pods.
+			// BlockOwnerDeletion needs update on researchsessions/finalizers (see rbac.yaml)
 			OwnerReferences: []v1.OwnerReference{
 				{
-					APIVersion: "research.example.com/v1",
-					Kind:       "ResearchSession",
-					Name:       currentObj.GetName(),
-					UID:        currentObj.GetUID(),
-					Controller: boolPtr(true),
-					// Remove BlockOwnerDeletion to avoid permission issues
-					// BlockOwnerDeletion: boolPtr(true),
+					APIVersion:         currentObj.GetAPIVersion(),
+					Kind:               currentObj.GetKind(),
+					Name:               currentObj.GetName(),
+					UID:                currentObj.GetUID(),
+					Controller:         boolPtr(true),
+					BlockOwnerDeletion: boolPtr(true),
 				},
 			},
 		},
 		Spec: batchv1.JobSpec{
-			BackoffLimit:          int32Ptr(3),
-			ActiveDeadlineSeconds: int64Ptr(1800), // 30 minute timeout for safety
+			BackoffLimit:            int32Ptr(int32(retries)),
+			ActiveDeadlineSeconds:   int64Ptr(timeout),
+			TTLSecondsAfterFinished: int32Ptr(jobTTLSeconds),
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: v1.ObjectMeta{
 					Labels: map[string]string{
@@ -240,7 +863,11 @@ func handleResearchSessionEvent(obj *unstructured.Unstructured) error {
 					Containers: []corev1.Container{
 						{
 							Name:  "claude-runner",
-							Image: claudeRunnerImage,
+							Image: image,
+							// The runner writes a one-line failure reason here; fall back to
+							// the log tail if it exits without writing one
+							TerminationMessagePath:   "/dev/termination-log",
+							TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
 							// 🔒 Container-level security (SCC-compatible, no privileged capabilities)
 							SecurityContext: &corev1.SecurityContext{
 								AllowPrivilegeEscalation: boolPtr(false),
@@ -260,9 +887,9 @@ func handleResearchSessionEvent(obj *unstructured.Unstructured) error {
 								{Name: "RESEARCH_SESSION_NAMESPACE", Value: namespace},
 								{Name: "PROMPT", Value: prompt},
 								{Name: "WEBSITE_URL", Value: websiteURL},
-								{Name: "LLM_MODEL", Value: model},
-								{Name: "LLM_TEMPERATURE", Value: fmt.Sprintf("%.2f", temperature)},
-								{Name: "LLM_MAX_TOKENS", Value: fmt.Sprintf("%d", maxTokens)},
+								{Name: "LLM_MODEL", Value: llm.model},
+								{Name: "LLM_TEMPERATURE", Value: fmt.Sprintf("%.2f", llm.temperature)},
+								{Name: "LLM_MAX_TOKENS", Value: fmt.Sprintf("%d", llm.maxTokens)},
 								{Name: "TIMEOUT", Value: fmt.Sprintf("%d", timeout)},
 								{Name: "BACKEND_API_URL", Value: os.Getenv("BACKEND_API_URL")},
 
@@ -271,8 +898,8 @@ func handleResearchSessionEvent(obj *unstructured.Unstructured) error {
 									Name: "ANTHROPIC_API_KEY",
 									ValueFrom: &corev1.EnvVarSource{
 										SecretKeyRef: &corev1.SecretKeySelector{
-											LocalObjectReference: corev1.LocalObjectReference{Name: "claude-research-secrets"},
-											Key:                  "anthropic-api-key",
+											LocalObjectReference: corev1.LocalObjectReference{Name: apiKeySecretName},
+											Key:                  apiKeySecretKey,
 										},
 									},
 								},
@@ -294,16 +921,7 @@ func handleResearchSessionEvent(obj *unstructured.Unstructured) error {
 								// { Name: "NO_PROXY",    Value: ".svc,.cluster.local,10.0.0.0/8" },
 							},
 
-							Resources: corev1.ResourceRequirements{
-								Requests: corev1.ResourceList{
-									corev1.ResourceCPU:    resource.MustParse("1000m"),
-									corev1.ResourceMemory: resource.MustParse("2Gi"),
-								},
-								Limits: corev1.ResourceList{
-									corev1.ResourceCPU:    resource.MustParse("2000m"),
-									corev1.ResourceMemory: resource.MustParse("4Gi"),
-								},
-							},
+							Resources: resources,
 						},
 					},
 				},
@@ -311,152 +929,831 @@ func handleResearchSessionEvent(obj *unstructured.Unstructured) error {
 		},
 	}
 
+	job.Spec.Template.Spec.ImagePullSecrets = pullSecrets
+	job.Spec.Template.Spec.ServiceAccountName = serviceAccount
+	job.Spec.Template.Spec.NodeSelector = scheduling.nodeSelector
+	job.Spec.Template.Spec.Tolerations = scheduling.tolerations
+	job.Spec.Template.Spec.Affinity = scheduling.affinity
+
+	// spec.env goes after the operator's own variables, which it and
+	// spec.envFrom may not replace
+	container := &job.Spec.Template.Spec.Containers[0]
+	managedEnv := container.Env
+	extraEnv, err := runnerEnv(spec, managedEnv)
+	if err != nil {
+		return failInvalidSpec(ctx, currentObj, err)
+	}
+	container.Env = append(container.Env, extraEnv...)
+
+	envFrom, err := runnerEnvFrom(spec)
+	if err != nil {
+		return failInvalidSpec(ctx, currentObj, err)
+	}
+	if problem, err := checkEnvFrom(ctx, namespace, envFrom, managedEnv); err != nil {
+		return err
+	} else if problem != "" {
+		logger.Warn("Invalid envFrom", "phase", "Failed", "problem", problem)
+		return rejectSpec(currentObj, invalidSpec("envFrom", problem))
+	}
+	container.EnvFrom = envFrom
+
+	// Large prompts go through a ConfigMap mounted into the pod, since the
+	// total env size on a pod is limited and overflowing it fails pod creation
+	// with an unhelpful error
+	usePromptFile := len(prompt) > promptFileBytes
+	if usePromptFile {
+		podSpec := &job.Spec.Template.Spec
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: "prompt",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: promptConfigMapName(jobName)},
+				},
+			},
+		})
+		container := &podSpec.Containers[0]
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name: "prompt", MountPath: promptMountPath, ReadOnly: true,
+		})
+		for i := range container.Env {
+			if container.Env[i].Name == "PROMPT" {
+				container.Env[i] = corev1.EnvVar{Name: "PROMPT_FILE", Value: promptMountPath + "/" + promptConfigMapKey}
+			}
+		}
+	}
+
+	if dryRun {
+		logDryRun(fmt.Sprintf("create job %s/%s", namespace, jobName), job)
+		if usePromptFile {
+			logDryRun("create prompt ConfigMap "+promptConfigMapName(jobName), promptConfigMap(job, prompt))
+		}
+		// The session stays Pending, so every resync lands here again
+		if dryRunFirstVisit(currentObj) {
+			jobsTotal.WithLabelValues("Running").Inc()
+		}
+		return nil
+	}
+
 	// Update status to Creating before attempting job creation
-	if err := updateResearchSessionStatus(name, map[string]interface{}{
-		"phase":   "Creating",
-		"message": "Creating Kubernetes job",
-	}); err != nil {
-		log.Printf("Failed to update ResearchSession status to Creating: %v", err)
+	if err := updateResearchSessionStatus(namespace, name, map[string]interface{}{
+		"phase":              "Creating",
+		"message":            "Creating Kubernetes job",
+		"observedGeneration": currentObj.GetGeneration(),
+	}, conditionUpdate{conditionSpecValid, "True", "Valid", "Spec passed validation"}); err != nil {
+		logger.Error("Failed to update status", "phase", "Creating", "err", err)
 		// Continue anyway - resource might have been deleted
 	}
 
 	// Create the job
-	_, err = k8sClient.BatchV1().Jobs(namespace).Create(context.TODO(), job, v1.CreateOptions{})
+	reqCtx, cancel = apiContext(context.Background())
+	createdJob, err := k8sClient.BatchV1().Jobs(namespace).Create(reqCtx, job, v1.CreateOptions{})
+	cancel()
 	if err != nil {
-		log.Printf("Failed to create job %s: %v", jobName, err)
+		logger.Error("Failed to create job", "job", jobName, "phase", "Error", "err", err)
+		jobsTotal.WithLabelValues("Error").Inc()
 		// Update status to Error if job creation fails and resource still exists
-		updateResearchSessionStatus(name, map[string]interface{}{
+		updateResearchSessionStatus(namespace, name, map[string]interface{}{
 			"phase":   "Error",
 			"message": fmt.Sprintf("Failed to create job: %v", err),
 		})
 		return fmt.Errorf("failed to create job: %v", err)
 	}
 
-	log.Printf("Created job %s for ResearchSession %s", jobName, name)
+	// The pod waits in ContainerCreating until the prompt ConfigMap exists;
+	// owning it by the job means it is cleaned up together with the job
+	if usePromptFile {
+		if err := createPromptConfigMap(createdJob, prompt); err != nil {
+			logger.Error("Failed to create prompt ConfigMap", "job", jobName, "phase", "Error", "err", err)
+			jobsTotal.WithLabelValues("Error").Inc()
+			propagation := v1.DeletePropagationBackground
+			reqCtx, cancel := apiContext(context.Background())
+			k8sClient.BatchV1().Jobs(namespace).Delete(reqCtx, jobName, v1.DeleteOptions{PropagationPolicy: &propagation})
+			cancel()
+			updateResearchSessionStatus(namespace, name, map[string]interface{}{
+				"phase":   "Error",
+				"message": fmt.Sprintf("Failed to create prompt ConfigMap: %v", err),
+			})
+			return fmt.Errorf("failed to create prompt ConfigMap: %v", err)
+		}
+		logger.Info("Passing prompt via ConfigMap", "job", jobName, "bytes", len(prompt), "configMap", promptConfigMapName(jobName))
+	}
+
+	logger.Info("Created job", "job", jobName, "phase", "Running")
+	jobsTotal.WithLabelValues("Running").Inc()
+	recordEvent(currentObj, corev1.EventTypeNormal, reasonJobCreated, "Created job %s", jobName)
 
 	// Update ResearchSession status to Running
-	if err := updateResearchSessionStatus(name, map[string]interface{}{
-		"phase":     "Running",
-		"message":   "Job created and running",
-		"startTime": time.Now().Format(time.RFC3339),
-		"jobName":   jobName,
+	if err := updateResearchSessionStatus(namespace, name, map[string]interface{}{
+		"phase":              "Running",
+		"message":            "Job created and running",
+		"startTime":          time.Now().Format(time.RFC3339),
+		"jobName":            jobName,
+		"runnerImage":        image,
+		"observedGeneration": currentObj.GetGeneration(),
 	}); err != nil {
-		log.Printf("Failed to update ResearchSession status to Running: %v", err)
+		logger.Error("Failed to update status", "phase", "Running", "err", err)
 		// Don't return error here - the job was created successfully
 		// The status update failure might be due to the resource being deleted
 	}
 
 	// Start monitoring the job
-	go monitorJob(jobName, name)
+	startMonitor(ctx, namespace, jobName, name)
 
 	return nil
 }
 
-func monitorJob(jobName, sessionName string) {
-	log.Printf("Starting job monitoring for %s (session: %s)", jobName, sessionName)
+const (
+	promptMountPath    = "/etc/research-prompt"
+	promptConfigMapKey = "prompt"
+)
+
+func promptConfigMapName(jobName string) string {
+	return fmt.Sprintf("%s-prompt", jobName)
+}
+
+// createPromptConfigMap stores the prompt in a ConfigMap owned by the job.
+func createPromptConfigMap(job *batchv1.Job, prompt string) error {
+	cm := promptConfigMap(job, prompt)
+
+	ctx, cancel := apiContext(context.Background())
+	defer cancel()
+
+	_, err := k8sClient.CoreV1().ConfigMaps(job.Namespace).Create(ctx, cm, v1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		// Left over from an earlier job with the same name; replace it
+		if err = k8sClient.CoreV1().ConfigMaps(job.Namespace).Delete(ctx, cm.Name, v1.DeleteOptions{}); err == nil || errors.IsNotFound(err) {
+			_, err = k8sClient.CoreV1().ConfigMaps(job.Namespace).Create(ctx, cm, v1.CreateOptions{})
+		}
+	}
+	return err
+}
+
+// promptConfigMap builds the ConfigMap holding a job's prompt, owned by the job.
+func promptConfigMap(job *batchv1.Job, prompt string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      promptConfigMapName(job.Name),
+			Namespace: job.Namespace,
+			Labels:    job.Labels,
+			OwnerReferences: []v1.OwnerReference{
+				{
+					APIVersion: "batch/v1",
+					Kind:       "Job",
+					Name:       job.Name,
+					UID:        job.UID,
+					Controller: boolPtr(true),
+				},
+			},
+		},
+		Data: map[string]string{promptConfigMapKey: prompt},
+	}
+}
+
+// reconcileRunningSession heals a Running session whose backing job is gone by
+// sending it back to Pending for a fresh job, and resumes monitoring a job
+// nobody is watching.
+func reconcileRunningSession(ctx context.Context, obj *unstructured.Unstructured) error {
+	logger := loggerFrom(ctx)
+	name := obj.GetName()
+	namespace := obj.GetNamespace()
+	jobName, _, _ := unstructured.NestedString(obj.Object, "status", "jobName")
+	if jobName == "" {
+		jobName = fmt.Sprintf("%s-job", name)
+	}
+
+	reqCtx, cancel := apiContext(ctx)
+	_, err := k8sClient.BatchV1().Jobs(namespace).Get(reqCtx, jobName, v1.GetOptions{})
+	cancel()
+	if errors.IsNotFound(err) {
+		logger.Warn("Job for running session is missing, recreating", "job", jobName, "phase", "Pending")
+		return updateResearchSessionStatus(namespace, name, map[string]interface{}{
+			"phase":   "Pending",
+			"message": fmt.Sprintf("Job %s was missing, recreating", jobName),
+			"jobName": nil,
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get job %s: %v", jobName, err)
+	}
+
+	if !hasMonitor(namespace, jobName) {
+		logger.Info("Resuming job monitoring", "job", jobName)
+		startMonitor(ctx, namespace, jobName, name)
+	}
+	return nil
+}
+
+// restartResearchSession deletes the session's job, waits for it to go away,
+// clears the restart annotation and resets the status to Pending with reason
+// as its message.
+func restartResearchSession(ctx context.Context, obj *unstructured.Unstructured, reason string) error {
+	logger := loggerFrom(ctx)
+	name := obj.GetName()
+	namespace := obj.GetNamespace()
+	jobName := fmt.Sprintf("%s-job", name)
+
+	logger.Info("Restarting session", "job", jobName, "reason", reason)
+
+	if dryRun {
+		logger.Info("[dry-run] Would delete job, remove the restart annotation and reset the session to Pending", "job", jobName)
+		return nil
+	}
+
+	// Stop the monitor first so it can't report the deleted job's outcome, then
+	// delete the job and its pods
+	stopMonitor(namespace, jobName)
+	propagation := v1.DeletePropagationBackground
+	reqCtx, cancel := apiContext(context.Background())
+	err := k8sClient.BatchV1().Jobs(namespace).Delete(reqCtx, jobName, v1.DeleteOptions{PropagationPolicy: &propagation})
+	cancel()
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete job %s for restart: %v", jobName, err)
+	}
+
+	// Wait for the job to be gone so the Pending handler doesn't see it as already existing
+	deadline := time.Now().Add(60 * time.Second)
+	for {
+		reqCtx, cancel := apiContext(ctx)
+		_, err := k8sClient.BatchV1().Jobs(namespace).Get(reqCtx, jobName, v1.GetOptions{})
+		cancel()
+		if errors.IsNotFound(err) {
+			break
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for job %s to be deleted", jobName)
+		}
+		if !sleepCtx(ctx, 2*time.Second) {
+			return ctx.Err()
+		}
+	}
+
+	// Clear the annotation first so the reset below doesn't trigger another restart
+	gvr := getResearchSessionResource()
+	reqCtx, cancel = apiContext(context.Background())
+	defer cancel()
+	fresh, err := dynamicClient.Resource(gvr).Namespace(namespace).Get(reqCtx, name, v1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get ResearchSession %s: %v", name, err)
+	}
+	annotations := fresh.GetAnnotations()
+	delete(annotations, restartAnnotation)
+	fresh.SetAnnotations(annotations)
+	if _, err := dynamicClient.Resource(gvr).Namespace(namespace).Update(reqCtx, fresh, v1.UpdateOptions{}); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to clear restart annotation on ResearchSession %s: %v", name, err)
+	}
+
+	// Reset to Pending, dropping results from the previous run
+	return updateResearchSessionStatus(namespace, name, map[string]interface{}{
+		"phase":           "Pending",
+		"message":         reason,
+		"startTime":       nil,
+		"completionTime":  nil,
+		"jobName":         nil,
+		"finalOutput":     nil,
+		"result":          nil,
+		"failedPod":       nil,
+		"exitCode":        nil,
+		"cost":            nil,
+		"messages":        nil,
+		"runnerLog":       nil,
+		"runnerImage":     nil,
+		"durationSeconds": nil,
+	})
+}
+
+// monitorJob checks the job until it finishes, the session or job is deleted,
+// or ctx is cancelled. A watch on the job triggers a check as soon as it
+// changes; polling every jobPollInterval covers a quiet or broken watch.
+func monitorJob(ctx context.Context, namespace, jobName, sessionName string) {
+	// Monitors are started from a reconcile, whose logger already carries the session
+	logger := loggerFrom(ctx).With("job", jobName)
+	logger.Info("Starting job monitoring")
+	monitorsInFlight.Inc()
+	defer monitorsInFlight.Dec()
+
+	jobWatch := watchJob(ctx, namespace, jobName)
+	defer func() {
+		if jobWatch != nil {
+			jobWatch.Stop()
+		}
+	}()
+	pollInterval := time.Duration(jobPollInterval.Load())
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
 
 	for {
-		time.Sleep(10 * time.Second)
+		var events <-chan watch.Event
+		if jobWatch != nil {
+			events = jobWatch.ResultChan()
+		}
+		select {
+		case <-ctx.Done():
+			logger.Info("Stopping job monitoring: monitor cancelled")
+			return
+		case <-ticker.C:
+			// Pick up a JOB_POLL_INTERVAL reloaded from CONFIG_DIR
+			if interval := time.Duration(jobPollInterval.Load()); interval != pollInterval {
+				pollInterval = interval
+				ticker.Reset(pollInterval)
+			}
+			// Reopen a dropped watch at most once per poll
+			if jobWatch == nil {
+				jobWatch = watchJob(ctx, namespace, jobName)
+			}
+		case event, ok := <-events:
+			// The API server ends watches periodically; poll until the next tick reopens it
+			if !ok || event.Type == watch.Error {
+				jobWatch.Stop()
+				jobWatch = nil
+				continue
+			}
+		}
 
 		// First check if the ResearchSession still exists
 		gvr := getResearchSessionResource()
-		if _, err := dynamicClient.Resource(gvr).Namespace(namespace).Get(context.TODO(), sessionName, v1.GetOptions{}); err != nil {
+		reqCtx, cancel := apiContext(ctx)
+		session, err := dynamicClient.Resource(gvr).Namespace(namespace).Get(reqCtx, sessionName, v1.GetOptions{})
+		cancel()
+		if err != nil {
 			if errors.IsNotFound(err) {
-				log.Printf("ResearchSession %s no longer exists, stopping job monitoring for %s", sessionName, jobName)
+				logger.Info("ResearchSession no longer exists, stopping job monitoring")
 				return
 			}
-			log.Printf("Error checking ResearchSession %s existence: %v", sessionName, err)
+			logger.Error("Error checking ResearchSession existence", "err", err)
 			// Continue monitoring even if we can't check the session
 		}
 
-		job, err := k8sClient.BatchV1().Jobs(namespace).Get(context.TODO(), jobName, v1.GetOptions{})
+		reqCtx, cancel = apiContext(ctx)
+		job, err := k8sClient.BatchV1().Jobs(namespace).Get(reqCtx, jobName, v1.GetOptions{})
+		cancel()
 		if err != nil {
 			if errors.IsNotFound(err) {
-				log.Printf("Job %s not found, stopping monitoring", jobName)
+				logger.Info("Job not found, stopping monitoring")
 				return
 			}
-			log.Printf("Error getting job %s: %v", jobName, err)
+			logger.Error("Error getting job", "err", err)
 			continue
 		}
+		logger.Debug("Polled job", "active", job.Status.Active, "succeeded", job.Status.Succeeded, "failed", job.Status.Failed)
 
 		// Check job status
 		if job.Status.Succeeded > 0 {
-			log.Printf("Job %s completed successfully", jobName)
+			logger.Info("Job completed successfully", "phase", "Completed")
+			jobsTotal.WithLabelValues("Completed").Inc()
+			if session != nil {
+				recordEvent(session, corev1.EventTypeNormal, reasonCompleted, "Job %s completed successfully", jobName)
+				notifySessionFinished(session, "Completed", "Job completed successfully")
+			}
 
 			// Update ResearchSession status to Completed
-			updateResearchSessionStatus(sessionName, map[string]interface{}{
+			completedStatus := map[string]interface{}{
 				"phase":          "Completed",
 				"message":        "Job completed successfully",
 				"completionTime": time.Now().Format(time.RFC3339),
+			}
+			if job.Status.StartTime != nil && job.Status.CompletionTime != nil {
+				duration := job.Status.CompletionTime.Sub(job.Status.StartTime.Time)
+				jobDuration.Observe(duration.Seconds())
+				completedStatus["durationSeconds"] = int64(duration.Round(time.Second).Seconds())
+			}
+			updateResearchSessionStatus(namespace, sessionName, completedStatus)
+			return
+		}
+
+		// A job killed by its ActiveDeadlineSeconds is a timeout, not a runner failure
+		failedCondition := jobFailedCondition(job)
+		if failedCondition != nil && failedCondition.Reason == batchv1.JobReasonDeadlineExceeded {
+			timeoutMessage := "Research session timed out"
+			if job.Spec.ActiveDeadlineSeconds != nil {
+				timeoutMessage = fmt.Sprintf("Research session timed out after %ds", *job.Spec.ActiveDeadlineSeconds)
+			}
+			logger.Warn("Job exceeded its deadline", "phase", "Failed")
+			jobsTotal.WithLabelValues("Failed").Inc()
+			if session != nil {
+				recordEvent(session, corev1.EventTypeWarning, reasonFailed, "%s (job %s)", timeoutMessage, jobName)
+				notifySessionFinished(session, "Failed", timeoutMessage)
+			}
+
+			updateResearchSessionStatus(namespace, sessionName, map[string]interface{}{
+				"phase":          "Failed",
+				"message":        timeoutMessage,
+				"completionTime": time.Now().Format(time.RFC3339),
 			})
 			return
 		}
 
-		if job.Status.Failed >= *job.Spec.BackoffLimit {
-			log.Printf("Job %s failed after %d attempts", jobName, job.Status.Failed)
+		// The Failed condition is authoritative; the attempt count covers clusters
+		// that haven't set it yet. Kubernetes gives up once failures exceed
+		// BackoffLimit, so a limit of N allows N retries after the first attempt
+		if failedCondition != nil || job.Status.Failed > jobBackoffLimit(job) {
+			logger.Warn("Job failed", "phase", "Failed", "attempts", job.Status.Failed)
+			jobsTotal.WithLabelValues("Failed").Inc()
 
 			// Get pod logs for error information
 			errorMessage := "Job failed"
-			if pods, err := k8sClient.CoreV1().Pods(namespace).List(context.TODO(), v1.ListOptions{
+			failureStatus := map[string]interface{}{}
+			reqCtx, cancel := apiContext(context.Background())
+			if pods, err := k8sClient.CoreV1().Pods(namespace).List(reqCtx, v1.ListOptions{
 				LabelSelector: fmt.Sprintf("job-name=%s", jobName),
 			}); err == nil && len(pods.Items) > 0 {
-				// Try to get logs from the first pod
-				pod := pods.Items[0]
-				if logs, err := k8sClient.CoreV1().Pods(namespace).GetLogs(pod.Name, &corev1.PodLogOptions{}).DoRaw(context.TODO()); err == nil {
+				// Diagnose from the latest attempt; earlier retries may have
+				// failed differently
+				pod := latestFailedPod(pods.Items)
+
+				// Record which pod failed and how, to tell evictions and OOM
+				// kills apart from runner errors. A pod that never terminated
+				// (e.g. deleted by the deadline) has no exit code
+				failureStatus["failedPod"] = pod.Name
+				if terminated := runnerTerminatedState(pod); terminated != nil {
+					failureStatus["exitCode"] = int64(terminated.ExitCode)
+				}
+
+				if msg := terminationMessage(pod); msg != "" {
+					errorMessage = fmt.Sprintf("Job failed: %s", msg)
+				} else if logs, err := k8sClient.CoreV1().Pods(namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+					Container: "claude-runner",
+					// After a container restart the failure is in the previous instance's logs
+					Previous: runnerRestarted(pod),
+				}).DoRaw(reqCtx); err == nil {
 					errorMessage = fmt.Sprintf("Job failed: %s", string(logs))
-					if len(errorMessage) > 500 {
-						errorMessage = errorMessage[:500] + "..."
-					}
 				}
+				if len(errorMessage) > 500 {
+					errorMessage = errorMessage[:500] + "..."
+				}
+			}
+			cancel()
+
+			if session != nil {
+				recordEvent(session, corev1.EventTypeWarning, reasonFailed, "%s (job %s)", errorMessage, jobName)
+				notifySessionFinished(session, "Failed", errorMessage)
 			}
 
 			// Update ResearchSession status to Failed
-			updateResearchSessionStatus(sessionName, map[string]interface{}{
-				"phase":          "Failed",
-				"message":        errorMessage,
-				"completionTime": time.Now().Format(time.RFC3339),
-			})
+			failureStatus["phase"] = "Failed"
+			failureStatus["message"] = errorMessage
+			failureStatus["completionTime"] = time.Now().Format(time.RFC3339)
+			updateResearchSessionStatus(namespace, sessionName, failureStatus)
 			return
 		}
+
+		// Still running: show the runner's latest output in status
+		if runnerLogTailLines > 0 && session != nil {
+			runnerLog, ok, err := tailRunnerLog(ctx, namespace, jobName)
+			if err != nil {
+				logger.Debug("Failed to tail runner log", "err", err)
+			} else if current, _, _ := unstructured.NestedString(session.Object, "status", "runnerLog"); ok && runnerLog != current {
+				if err := updateResearchSessionStatus(namespace, sessionName, map[string]interface{}{"runnerLog": runnerLog}); err != nil {
+					logger.Error("Failed to update runner log", "err", err)
+				}
+			}
+		}
 	}
 }
 
-func updateResearchSessionStatus(name string, statusUpdate map[string]interface{}) error {
-	gvr := getResearchSessionResource()
-
-	// Get current resource
-	obj, err := dynamicClient.Resource(gvr).Namespace(namespace).Get(context.TODO(), name, v1.GetOptions{})
+// runnerPullSecrets combines IMAGE_PULL_SECRETS with the session's own
+// spec.imagePullSecrets, dropping duplicates.
+func runnerPullSecrets(spec map[string]interface{}) ([]corev1.LocalObjectReference, error) {
+	names := append([]string{}, imagePullSecrets...)
+	refs, _, err := specSlice(spec, "imagePullSecrets")
 	if err != nil {
-		if errors.IsNotFound(err) {
-			log.Printf("ResearchSession %s no longer exists, skipping status update", name)
-			return nil // Don't treat this as an error - resource was deleted
+		return nil, err
+	}
+	for i, ref := range refs {
+		m, ok := ref.(map[string]interface{})
+		if !ok {
+			return nil, specTypeError([]string{fmt.Sprintf("imagePullSecrets[%d]", i)}, "an object", ref)
+		}
+		name, isString := m["name"].(string)
+		if !isString && m["name"] != nil {
+			return nil, specTypeError([]string{fmt.Sprintf("imagePullSecrets[%d]", i), "name"}, "a string", m["name"])
+		}
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+
+	var secrets []corev1.LocalObjectReference
+	seen := map[string]bool{}
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			secrets = append(secrets, corev1.LocalObjectReference{Name: name})
 		}
-		return fmt.Errorf("failed to get ResearchSession %s: %v", name, err)
 	}
+	return secrets, nil
+}
 
-	// Update status
-	if obj.Object["status"] == nil {
-		obj.Object["status"] = make(map[string]interface{})
+// runnerResources builds the runner container's resource requirements from
+// the given requests/limits (keyed by resource name), falling back to the
+// defaults for anything unset.
+func runnerResources(requests, limits map[string]string) (corev1.ResourceRequirements, error) {
+	result := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{},
+		Limits:   corev1.ResourceList{},
 	}
 
-	status := obj.Object["status"].(map[string]interface{})
-	for key, value := range statusUpdate {
-		status[key] = value
+	for _, r := range []struct {
+		list     corev1.ResourceList
+		values   map[string]string
+		name     corev1.ResourceName
+		fallback string
+	}{
+		{result.Requests, requests, corev1.ResourceCPU, "1000m"},
+		{result.Requests, requests, corev1.ResourceMemory, "2Gi"},
+		{result.Limits, limits, corev1.ResourceCPU, "2000m"},
+		{result.Limits, limits, corev1.ResourceMemory, "4Gi"},
+	} {
+		q, err := parseQuantityOrDefault(r.values[string(r.name)], r.fallback)
+		if err != nil {
+			return corev1.ResourceRequirements{}, fmt.Errorf("%s: %v", r.name, err)
+		}
+		r.list[r.name] = q
 	}
 
-	// Update the resource with retry logic
-	_, err = dynamicClient.Resource(gvr).Namespace(namespace).UpdateStatus(context.TODO(), obj, v1.UpdateOptions{})
+	// A limit below a defaulted request lowers the request, as Kubernetes does
+	// for pods with only limits set. An explicit request over its limit is an
+	// error; the API server would reject the job, but only after it is marked Creating
+	for name, limit := range result.Limits {
+		request := result.Requests[name]
+		if request.Cmp(limit) <= 0 {
+			continue
+		}
+		if requests[string(name)] == "" {
+			result.Requests[name] = limit
+			continue
+		}
+		return corev1.ResourceRequirements{}, fmt.Errorf("%s request %s exceeds limit %s", name, request.String(), limit.String())
+	}
+
+	return result, nil
+}
+
+// quantityStrings converts a spec resource map to strings, accepting bare
+// numbers like `cpu: 2` as well as quoted quantities.
+func quantityStrings(values map[string]interface{}) map[string]string {
+	result := make(map[string]string, len(values))
+	for name, value := range values {
+		switch v := value.(type) {
+		case string:
+			result[name] = v
+		case int64:
+			result[name] = strconv.FormatInt(v, 10)
+		case float64:
+			result[name] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			result[name] = fmt.Sprint(v)
+		}
+	}
+	return result
+}
+
+// parseQuantityOrDefault parses value as a resource quantity, using fallback
+// when value is empty. Unlike resource.MustParse it never panics, so malformed
+// user input can be reported instead of crashing the operator.
+func parseQuantityOrDefault(value, fallback string) (resource.Quantity, error) {
+	if value == "" {
+		value = fallback
+	}
+	q, err := resource.ParseQuantity(value)
 	if err != nil {
+		return resource.Quantity{}, fmt.Errorf("invalid quantity %q: %v", value, err)
+	}
+	return q, nil
+}
+
+// truncateUTF8 cuts s to at most n bytes without splitting a multi-byte rune.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// checkServiceAccount verifies the runner's ServiceAccount exists; without it
+// the job controller can't create pods and the session would sit in Running
+// until its deadline. It returns a problem or an error like checkAPIKeySecret.
+func checkServiceAccount(ctx context.Context, namespace, name string) (string, error) {
+	reqCtx, cancel := apiContext(ctx)
+	defer cancel()
+	_, err := k8sClient.CoreV1().ServiceAccounts(namespace).Get(reqCtx, name, v1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return fmt.Sprintf("ServiceAccount %q not found in namespace %s", name, namespace), nil
+	}
+	return "", err
+}
+
+// checkAPIKeySecret verifies the secret referenced for the runner's API key
+// exists and has the key, so a bad reference fails the session up front
+// instead of leaving the pod stuck in CreateContainerConfigError. It returns a
+// user-facing problem for a bad reference and an error for API failures, which
+// are worth retrying.
+func checkAPIKeySecret(ctx context.Context, namespace, secretName, secretKey string) (string, error) {
+	reqCtx, cancel := apiContext(ctx)
+	defer cancel()
+	secret, err := k8sClient.CoreV1().Secrets(namespace).Get(reqCtx, secretName, v1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return fmt.Sprintf("API key secret %q not found in namespace %s", secretName, namespace), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if _, ok := secret.Data[secretKey]; !ok {
+		return fmt.Sprintf("API key secret %q has no key %q", secretName, secretKey), nil
+	}
+	return "", nil
+}
+
+// jobBackoffLimit returns the job's BackoffLimit, treating an unset value as
+// the Kubernetes default since the job may have been edited outside the operator.
+func jobBackoffLimit(job *batchv1.Job) int32 {
+	if job.Spec.BackoffLimit == nil {
+		return defaultJobBackoffLimit
+	}
+	return *job.Spec.BackoffLimit
+}
+
+// jobFailedCondition returns the job's Failed condition if the job controller
+// has marked it terminally failed (e.g. DeadlineExceeded, BackoffLimitExceeded).
+func jobFailedCondition(job *batchv1.Job) *batchv1.JobCondition {
+	for i := range job.Status.Conditions {
+		c := &job.Status.Conditions[i]
+		if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue {
+			return c
+		}
+	}
+	return nil
+}
+
+// latestFailedPod picks the pod to diagnose a failed job from: the newest
+// failed pod, or the newest pod if none has failed yet.
+func latestFailedPod(pods []corev1.Pod) *corev1.Pod {
+	var latest, latestFailed *corev1.Pod
+	for i := range pods {
+		pod := &pods[i]
+		if latest == nil || podNewer(pod, latest) {
+			latest = pod
+		}
+		if pod.Status.Phase == corev1.PodFailed && (latestFailed == nil || podNewer(pod, latestFailed)) {
+			latestFailed = pod
+		}
+	}
+	if latestFailed != nil {
+		return latestFailed
+	}
+	return latest
+}
+
+// podNewer orders pods by creation timestamp, then name for pods created in
+// the same second.
+func podNewer(a, b *corev1.Pod) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return b.CreationTimestamp.Before(&a.CreationTimestamp)
+	}
+	return a.Name > b.Name
+}
+
+// runnerRestarted reports whether the runner container has restarted in place.
+func runnerRestarted(pod *corev1.Pod) bool {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name == "claude-runner" {
+			return cs.RestartCount > 0
+		}
+	}
+	return false
+}
+
+// runnerTerminatedState returns how the runner container last terminated:
+// its current state, or its previous one if it has restarted since.
+func runnerTerminatedState(pod *corev1.Pod) *corev1.ContainerStateTerminated {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name != "claude-runner" {
+			continue
+		}
+		if cs.State.Terminated != nil {
+			return cs.State.Terminated
+		}
+		return cs.LastTerminationState.Terminated
+	}
+	return nil
+}
+
+// terminationMessage returns the runner container's termination message, or
+// "" if it didn't report one. Sidecars' messages are ignored so they can't pass
+// for the runner's output.
+func terminationMessage(pod *corev1.Pod) string {
+	if t := runnerTerminatedState(pod); t != nil {
+		return strings.TrimSpace(t.Message)
+	}
+	return ""
+}
+
+// updateResearchSessionStatus writes statusUpdate to the session's status. A
+// phase change also updates the phase conditions; conditions are set after those.
+func updateResearchSessionStatus(namespace, name string, statusUpdate map[string]interface{}, conditions ...conditionUpdate) error {
+	gvr := getResearchSessionResource()
+
+	if dryRun {
+		logDryRun(fmt.Sprintf("update status of ResearchSession %s/%s", namespace, name), statusUpdate)
+		statusUpdatesTotal.WithLabelValues("success").Inc()
+		return nil
+	}
+
+	// Only the keys being changed are sent, so fields other writers set in the
+	// meantime survive. A nil value becomes null, which removes the key
+	deleted := false
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		statusPatch := make(map[string]interface{}, len(statusUpdate)+1)
+		for key, value := range statusUpdate {
+			statusPatch[key] = value
+		}
+		patch := map[string]interface{}{"status": statusPatch}
+
+		// Conditions are derived from the current ones, so a phase change reads
+		// the object and pins its resourceVersion; a conflict re-reads and retries
+		if phase, ok := statusUpdate["phase"].(string); ok || len(conditions) > 0 {
+			ctx, cancel := apiContext(context.Background())
+			obj, err := dynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, name, v1.GetOptions{})
+			cancel()
+			if err != nil {
+				if errors.IsNotFound(err) {
+					slog.Info("ResearchSession no longer exists, skipping status update", "namespace", namespace, "session", name)
+					deleted = true
+					return nil // Don't treat this as an error - resource was deleted
+				}
+				return fmt.Errorf("failed to get ResearchSession %s: %v", name, err)
+			}
+
+			status, _, _ := unstructured.NestedMap(obj.Object, "status")
+			if status == nil {
+				status = map[string]interface{}{}
+			}
+			message, _ := status["message"].(string)
+			if value, ok := statusUpdate["message"]; ok {
+				message, _ = value.(string)
+			}
+			if phase != "" {
+				setPhaseConditions(status, phase, message)
+			}
+			for _, c := range conditions {
+				setCondition(status, c.condType, c.status, c.reason, c.message)
+			}
+			statusPatch["conditions"] = status["conditions"]
+			patch["metadata"] = map[string]interface{}{"resourceVersion": obj.GetResourceVersion()}
+		}
+
+		err := patchStatus(gvr, namespace, name, patch)
 		if errors.IsNotFound(err) {
-			log.Printf("ResearchSession %s was deleted during status update, skipping", name)
+			slog.Info("ResearchSession was deleted during status update, skipping", "namespace", namespace, "session", name)
+			deleted = true
 			return nil // Don't treat this as an error - resource was deleted
 		}
+		return err
+	})
+	if err != nil {
+		statusUpdatesTotal.WithLabelValues("error").Inc()
 		return fmt.Errorf("failed to update ResearchSession status: %v", err)
 	}
+	if !deleted {
+		statusUpdatesTotal.WithLabelValues("success").Inc()
+	}
 
 	return nil
 }
 
+// failSession marks a session Failed with message, recording the generation
+// that was rejected so an edited spec is retried.
+func failSession(obj *unstructured.Unstructured, message string, conditions ...conditionUpdate) error {
+	return updateResearchSessionStatus(obj.GetNamespace(), obj.GetName(), map[string]interface{}{
+		"phase":              "Failed",
+		"message":            message,
+		"completionTime":     time.Now().Format(time.RFC3339),
+		"observedGeneration": obj.GetGeneration(),
+	}, conditions...)
+}
+
+// patchStatus applies patch to the named resource's status subresource as a
+// JSON merge patch.
+func patchStatus(gvr schema.GroupVersionResource, namespace, name string, patch map[string]interface{}) error {
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return fmt.Errorf("failed to encode status patch: %v", err)
+	}
+
+	ctx, cancel := apiContext(context.Background())
+	defer cancel()
+	_, err = dynamicClient.Resource(gvr).Namespace(namespace).Patch(ctx, name, types.MergePatchType, data, v1.PatchOptions{}, "status")
+	return err
+}
+
 var (
 	boolPtr  = func(b bool) *bool { return &b }
 	int32Ptr = func(i int32) *int32 { return &i }
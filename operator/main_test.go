@@ -0,0 +1,406 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestParseQuantityOrDefault(t *testing.T) {
+	tests := []struct {
+		value, fallback string
+		want            string
+		wantErr         bool
+	}{
+		{value: "", fallback: "2Gi", want: "2Gi"},
+		{value: "512Mi", fallback: "2Gi", want: "512Mi"},
+		{value: "4Gigs", fallback: "2Gi", wantErr: true},
+		{value: "lots", fallback: "1000m", wantErr: true},
+	}
+	for _, tt := range tests {
+		// A malformed quantity must come back as an error, not a panic
+		q, err := parseQuantityOrDefault(tt.value, tt.fallback)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseQuantityOrDefault(%q) = %s, want an error", tt.value, q.String())
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseQuantityOrDefault(%q): %v", tt.value, err)
+		} else if q.String() != tt.want {
+			t.Errorf("parseQuantityOrDefault(%q) = %s, want %s", tt.value, q.String(), tt.want)
+		}
+	}
+}
+
+func TestRunnerResourcesRejectsMalformedQuantity(t *testing.T) {
+	if _, err := runnerResources(map[string]string{"memory": "4Gigs"}, nil); err == nil {
+		t.Error("runnerResources accepted memory 4Gigs")
+	}
+}
+
+func TestUpdateResearchSessionStatusRetriesConflict(t *testing.T) {
+	dyn, _ := useFakeClients(t, newSession("s1", nil, map[string]interface{}{"phase": "Creating"}))
+
+	// The first patch loses a race with another writer
+	patches, gets := 0, 0
+	dyn.PrependReactor("get", "researchsessions", func(k8stesting.Action) (bool, runtime.Object, error) {
+		gets++
+		return false, nil, nil
+	})
+	dyn.PrependReactor("patch", "researchsessions", func(k8stesting.Action) (bool, runtime.Object, error) {
+		patches++
+		if patches == 1 {
+			return true, nil, errors.NewConflict(schema.GroupResource{Group: "research.example.com", Resource: "researchsessions"}, "s1", nil)
+		}
+		return false, nil, nil
+	})
+
+	if err := updateResearchSessionStatus("default", "s1", map[string]interface{}{"phase": "Running", "jobName": "s1-job"}); err != nil {
+		t.Fatal(err)
+	}
+	if patches != 2 || gets != 2 {
+		t.Errorf("patches = %d, gets = %d; want the object re-read and the patch retried once", patches, gets)
+	}
+
+	obj, err := dynamicClient.Resource(getResearchSessionResource()).Namespace("default").Get(context.Background(), "s1", v1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase"); phase != "Running" {
+		t.Errorf("phase = %q, want Running", phase)
+	}
+}
+
+func TestJobFailedCondition(t *testing.T) {
+	job := &batchv1.Job{Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+		{Type: batchv1.JobSuspended, Status: corev1.ConditionFalse},
+		{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: batchv1.JobReasonDeadlineExceeded},
+	}}}
+	if c := jobFailedCondition(job); c == nil || c.Reason != batchv1.JobReasonDeadlineExceeded {
+		t.Errorf("jobFailedCondition = %v, want the DeadlineExceeded condition", c)
+	}
+
+	// A Failed condition that isn't True hasn't failed the job yet
+	job.Status.Conditions[1].Status = corev1.ConditionFalse
+	if c := jobFailedCondition(job); c != nil {
+		t.Errorf("jobFailedCondition = %v, want nil", c)
+	}
+}
+
+func TestSortByCreation(t *testing.T) {
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	session := func(name string, created time.Time) unstructured.Unstructured {
+		obj := newSession(name, nil, nil)
+		obj.SetCreationTimestamp(v1.NewTime(created))
+		return *obj
+	}
+	items := []unstructured.Unstructured{
+		session("newest", base.Add(time.Hour)),
+		session("b", base),
+		session("oldest", base.Add(-time.Hour)),
+		session("a", base),
+	}
+
+	sortByCreation(items)
+
+	want := []string{"oldest", "a", "b", "newest"}
+	for i, item := range items {
+		if item.GetName() != want[i] {
+			t.Fatalf("order = %v, want %v", sessionNames(items), want)
+		}
+	}
+}
+
+func sessionNames(items []unstructured.Unstructured) []string {
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = item.GetName()
+	}
+	return names
+}
+
+func TestTerminationMessage(t *testing.T) {
+	pod := &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+		// A sidecar that exited first must not supply the runner's failure reason
+		{Name: "proxy", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			ExitCode: 0,
+			Message:  "proxy shutting down",
+		}}},
+		{Name: "claude-runner", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			ExitCode: 1,
+			Message:  "  API key rejected\n",
+		}}},
+	}}}
+	if got := terminationMessage(pod); got != "API key rejected" {
+		t.Errorf("terminationMessage = %q, want %q", got, "API key rejected")
+	}
+
+	pod.Status.ContainerStatuses[1].State.Terminated.Message = ""
+	if got := terminationMessage(pod); got != "" {
+		t.Errorf("terminationMessage = %q, want empty", got)
+	}
+}
+
+func TestLatestFailedPod(t *testing.T) {
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	pod := func(name string, created time.Time, phase corev1.PodPhase) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: v1.ObjectMeta{Name: name, CreationTimestamp: v1.NewTime(created)},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+	}
+
+	pods := []corev1.Pod{
+		pod("first", base, corev1.PodFailed),
+		pod("second", base.Add(time.Minute), corev1.PodFailed),
+		pod("retry", base.Add(2*time.Minute), corev1.PodRunning),
+	}
+	if got := latestFailedPod(pods); got.Name != "second" {
+		t.Errorf("latestFailedPod = %s, want the newest failed pod", got.Name)
+	}
+
+	pods = []corev1.Pod{pod("a", base, corev1.PodPending), pod("b", base, corev1.PodRunning)}
+	if got := latestFailedPod(pods); got.Name != "b" {
+		t.Errorf("latestFailedPod = %s, want the newest pod when none failed", got.Name)
+	}
+}
+
+func TestRestartResearchSession(t *testing.T) {
+	useOperatorConfig(t, time.Hour)
+	session := newSession("s1", map[string]interface{}{
+		"prompt":     "Summarize the site",
+		"websiteURL": "https://93.184.216.34/",
+	}, map[string]interface{}{
+		"phase":       "Completed",
+		"jobName":     "s1-job",
+		"finalOutput": "Done",
+		"exitCode":    int64(0),
+	})
+	session.SetAnnotations(map[string]string{restartAnnotation: "true", "keep": "me"})
+	job := &batchv1.Job{ObjectMeta: v1.ObjectMeta{Name: "s1-job", Namespace: "default"}}
+	_, k8s := useFakeClients(t, session, job, apiKeySecret())
+
+	// The annotation sends the reconcile down the restart path
+	if err := handleResearchSessionEvent(context.Background(), session); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := k8s.BatchV1().Jobs("default").Get(context.Background(), "s1-job", v1.GetOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("job still exists: %v", err)
+	}
+	obj, err := dynamicClient.Resource(getResearchSessionResource()).Namespace("default").Get(context.Background(), "s1", v1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := obj.GetAnnotations()[restartAnnotation]; ok {
+		t.Error("restart annotation was not cleared")
+	}
+	if obj.GetAnnotations()["keep"] != "me" {
+		t.Error("other annotations were dropped")
+	}
+	status, _, _ := unstructured.NestedMap(obj.Object, "status")
+	if status["phase"] != "Pending" || status["message"] != "Restart requested" {
+		t.Errorf("phase = %v, message = %v; want Pending, Restart requested", status["phase"], status["message"])
+	}
+	for _, key := range []string{"jobName", "finalOutput", "exitCode"} {
+		if _, ok := status[key]; ok {
+			t.Errorf("status.%s was not cleared", key)
+		}
+	}
+
+	// The reset to Pending re-enters the handler, which runs the session again
+	if err := handleResearchSessionEvent(context.Background(), obj); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := k8s.BatchV1().Jobs("default").Get(context.Background(), "s1-job", v1.GetOptions{}); err != nil {
+		t.Errorf("no new job after the restart: %v", err)
+	}
+	obj, err = dynamicClient.Resource(getResearchSessionResource()).Namespace("default").Get(context.Background(), "s1", v1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase"); phase != "Running" {
+		t.Errorf("phase after the rerun = %q, want Running", phase)
+	}
+}
+
+func TestTruncateUTF8(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{s: "hello", n: 10, want: "hello"},
+		{s: "hello", n: 3, want: "hel"},
+		// é is two bytes; cutting inside it drops the whole rune
+		{s: "café", n: 4, want: "caf"},
+		{s: "日本", n: 5, want: "日"},
+		{s: "日本", n: 2, want: ""},
+	}
+	for _, tt := range tests {
+		if got := truncateUTF8(tt.s, tt.n); got != tt.want {
+			t.Errorf("truncateUTF8(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestOversizedPromptIsRejected(t *testing.T) {
+	useOperatorConfig(t, time.Hour)
+	maxPromptBytes = 16
+
+	session := newSession("s1", map[string]interface{}{
+		"prompt":     "Summarize every page on the site",
+		"websiteURL": "https://93.184.216.34/",
+	}, map[string]interface{}{"phase": "Pending"})
+	_, k8s := useFakeClients(t, session, apiKeySecret())
+
+	if err := handleResearchSessionEvent(context.Background(), session); err != nil {
+		t.Fatal(err)
+	}
+
+	if cond := sessionCondition(t, "s1", conditionSpecValid); cond == nil || cond["reason"] != "InvalidPrompt" {
+		t.Errorf("SpecValid = %v, want reason InvalidPrompt", cond)
+	}
+	if jobs, _ := k8s.BatchV1().Jobs("default").List(context.Background(), v1.ListOptions{}); len(jobs.Items) != 0 {
+		t.Errorf("created %d jobs for a rejected prompt", len(jobs.Items))
+	}
+}
+
+func TestPromptConfigMap(t *testing.T) {
+	job := &batchv1.Job{ObjectMeta: v1.ObjectMeta{
+		Name:      "s1-job",
+		Namespace: "default",
+		UID:       "job-uid",
+		Labels:    map[string]string{"research-session": "s1"},
+	}}
+	_, k8s := useFakeClients(t, &corev1.ConfigMap{
+		ObjectMeta: v1.ObjectMeta{Name: "s1-job-prompt", Namespace: "default"},
+		Data:       map[string]string{promptConfigMapKey: "stale prompt"},
+	})
+
+	// A ConfigMap left over from an earlier job of the same name is replaced
+	if err := createPromptConfigMap(job, "Summarize the site"); err != nil {
+		t.Fatal(err)
+	}
+
+	cm, err := k8s.CoreV1().ConfigMaps("default").Get(context.Background(), "s1-job-prompt", v1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cm.Data[promptConfigMapKey] != "Summarize the site" {
+		t.Errorf("prompt = %q, want the new prompt", cm.Data[promptConfigMapKey])
+	}
+	if len(cm.OwnerReferences) != 1 || cm.OwnerReferences[0].UID != "job-uid" || cm.OwnerReferences[0].Kind != "Job" {
+		t.Errorf("owner references = %v, want the job", cm.OwnerReferences)
+	}
+	if cm.Labels["research-session"] != "s1" {
+		t.Errorf("labels = %v, want the job's labels", cm.Labels)
+	}
+}
+
+func TestLargePromptReachesRunnerAsFile(t *testing.T) {
+	useOperatorConfig(t, time.Hour)
+	promptFileBytes = 64
+	prompt := strings.Repeat("Summarize every page on the site. ", 4)
+	session := newSession("s1", map[string]interface{}{
+		"prompt":     prompt,
+		"websiteURL": "https://93.184.216.34/",
+	}, map[string]interface{}{"phase": "Pending"})
+	_, k8s := useFakeClients(t, session, apiKeySecret())
+
+	if err := handleResearchSessionEvent(context.Background(), session); err != nil {
+		t.Fatal(err)
+	}
+
+	job, err := k8s.BatchV1().Jobs("default").Get(context.Background(), "s1-job", v1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	container := runnerContainer(t, job)
+	env := map[string]string{}
+	for _, v := range container.Env {
+		env[v.Name] = v.Value
+	}
+	if _, ok := env["PROMPT"]; ok {
+		t.Error("PROMPT env is still set")
+	}
+	if env["PROMPT_FILE"] != promptMountPath+"/"+promptConfigMapKey {
+		t.Errorf("PROMPT_FILE = %q, want %s/%s", env["PROMPT_FILE"], promptMountPath, promptConfigMapKey)
+	}
+
+	var volume *corev1.Volume
+	for i := range job.Spec.Template.Spec.Volumes {
+		if v := &job.Spec.Template.Spec.Volumes[i]; v.Name == "prompt" {
+			volume = v
+		}
+	}
+	if volume == nil || volume.ConfigMap == nil || volume.ConfigMap.Name != "s1-job-prompt" {
+		t.Errorf("prompt volume = %v, want the s1-job-prompt ConfigMap", volume)
+	}
+	mounted := false
+	for _, m := range container.VolumeMounts {
+		mounted = mounted || m.Name == "prompt" && m.MountPath == promptMountPath && m.ReadOnly
+	}
+	if !mounted {
+		t.Errorf("mounts = %v, want prompt read-only at %s", container.VolumeMounts, promptMountPath)
+	}
+
+	cm, err := k8s.CoreV1().ConfigMaps("default").Get(context.Background(), "s1-job-prompt", v1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cm.Data[promptConfigMapKey] != prompt {
+		t.Errorf("ConfigMap prompt = %q, want %q", cm.Data[promptConfigMapKey], prompt)
+	}
+	if owners := cm.OwnerReferences; len(owners) != 1 || owners[0].Kind != "Job" || owners[0].Name != "s1-job" {
+		t.Errorf("owner references = %v, want the job", owners)
+	}
+}
+
+func TestOversizedPromptIsTruncated(t *testing.T) {
+	useOperatorConfig(t, time.Hour)
+	// The limit falls inside the two-byte é, which must not be split
+	maxPromptBytes, promptTruncate = 18, true
+	session := newSession("s1", map[string]interface{}{
+		"prompt":     "Summarize the café's menu",
+		"websiteURL": "https://93.184.216.34/",
+	}, map[string]interface{}{"phase": "Pending"})
+	_, k8s := useFakeClients(t, session, apiKeySecret())
+
+	if err := handleResearchSessionEvent(context.Background(), session); err != nil {
+		t.Fatal(err)
+	}
+
+	job, err := k8s.BatchV1().Jobs("default").Get(context.Background(), "s1-job", v1.GetOptions{})
+	if err != nil {
+		t.Fatalf("no job for a truncated prompt: %v", err)
+	}
+	var prompt string
+	for _, v := range runnerContainer(t, job).Env {
+		if v.Name == "PROMPT" {
+			prompt = v.Value
+		}
+	}
+	if want := "Summarize the caf"; prompt != want {
+		t.Errorf("PROMPT = %q, want %q", prompt, want)
+	}
+	obj, err := dynamicClient.Resource(getResearchSessionResource()).Namespace("default").Get(context.Background(), "s1", v1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase"); phase != "Running" {
+		t.Errorf("phase = %q, want Running", phase)
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"os"
+	"runtime"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/client-go/util/workqueue"
+)
+
+var (
+	// jobsTotal counts ResearchSession job outcomes by the phase they moved the session to
+	jobsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "researchsession_jobs_total",
+		Help: "ResearchSession job transitions, by resulting phase.",
+	}, []string{"phase"})
+
+	// statusUpdatesTotal counts ResearchSession status writes by result
+	statusUpdatesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "researchsession_status_updates_total",
+		Help: "ResearchSession status updates, by result.",
+	}, []string{"result"})
+
+	// reconcileDuration times handleResearchSessionEvent, separating slow
+	// reconciles from slow jobs
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "researchsession_reconcile_duration_seconds",
+		Help:    "Time spent in a ResearchSession reconcile, by result (success, error or requeue).",
+		Buckets: prometheus.ExponentialBuckets(0.001, 4, 10),
+	}, []string{"result"})
+
+	// reconcileRequeues counts reconciles that put their key back on the queue,
+	// whether after an error or to wait out a drain or the job limit
+	reconcileRequeues = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "researchsession_reconcile_requeues_total",
+		Help: "ResearchSession reconciles that requeued their key.",
+	})
+
+	// jobDuration tracks how long successful runner jobs take, to spot creep
+	jobDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "researchsession_job_duration_seconds",
+		Help:    "Run time of successful ResearchSession jobs, from the job's start to its completion.",
+		Buckets: prometheus.ExponentialBuckets(15, 2, 9),
+	})
+
+	// monitorsInFlight tracks running monitorJob goroutines
+	monitorsInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "researchsession_job_monitors_in_flight",
+		Help: "Number of jobs currently being monitored.",
+	})
+
+	// Work queue metrics, labelled by queue name, surface reconcile backpressure
+	queueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "researchsession_event_queue_depth",
+		Help: "Number of keys waiting in the reconcile work queue.",
+	}, []string{"name"})
+	queueAdds = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "researchsession_event_queue_adds_total",
+		Help: "Keys added to the reconcile work queue.",
+	}, []string{"name"})
+	queueRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "researchsession_event_queue_retries_total",
+		Help: "Keys requeued with backoff after a failed reconcile.",
+	}, []string{"name"})
+	queueLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "researchsession_event_queue_latency_seconds",
+		Help:    "Time a key waits in the work queue before being reconciled.",
+		Buckets: prometheus.ExponentialBuckets(0.001, 4, 10),
+	}, []string{"name"})
+	queueWorkDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "researchsession_event_queue_work_duration_seconds",
+		Help:    "Time spent reconciling a key taken from the work queue.",
+		Buckets: prometheus.ExponentialBuckets(0.001, 4, 10),
+	}, []string{"name"})
+	queueUnfinishedWork = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "researchsession_event_queue_unfinished_work_seconds",
+		Help: "Seconds of work in progress that has not yet finished.",
+	}, []string{"name"})
+	queueLongestRunning = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "researchsession_event_queue_longest_running_processor_seconds",
+		Help: "Seconds the longest running reconcile has been running.",
+	}, []string{"name"})
+)
+
+func registerMetrics() {
+	// Counters of writes carry dry_run so rehearsals aren't mistaken for real activity
+	prometheus.WrapRegistererWith(prometheus.Labels{"dry_run": dryRunLabel()}, prometheus.DefaultRegisterer).
+		MustRegister(jobsTotal, statusUpdatesTotal)
+	prometheus.MustRegister(monitorsInFlight)
+	prometheus.MustRegister(reconcileDuration, reconcileRequeues, jobDuration)
+	prometheus.MustRegister(buildInfo)
+	buildInfo.WithLabelValues(version, commit, buildDate, runtime.Version()).Set(1)
+	prometheus.MustRegister(queueDepth, queueAdds, queueRetries, queueLatency,
+		queueWorkDuration, queueUnfinishedWork, queueLongestRunning)
+}
+
+// queueMetricsProvider feeds client-go work queue metrics into Prometheus.
+type queueMetricsProvider struct{}
+
+func (queueMetricsProvider) NewDepthMetric(name string) workqueue.GaugeMetric {
+	return queueDepth.WithLabelValues(name)
+}
+
+func (queueMetricsProvider) NewAddsMetric(name string) workqueue.CounterMetric {
+	return queueAdds.WithLabelValues(name)
+}
+
+func (queueMetricsProvider) NewLatencyMetric(name string) workqueue.HistogramMetric {
+	return queueLatency.WithLabelValues(name)
+}
+
+func (queueMetricsProvider) NewWorkDurationMetric(name string) workqueue.HistogramMetric {
+	return queueWorkDuration.WithLabelValues(name)
+}
+
+func (queueMetricsProvider) NewUnfinishedWorkSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return queueUnfinishedWork.WithLabelValues(name)
+}
+
+func (queueMetricsProvider) NewLongestRunningProcessorSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return queueLongestRunning.WithLabelValues(name)
+}
+
+func (queueMetricsProvider) NewRetriesMetric(name string) workqueue.CounterMetric {
+	return queueRetries.WithLabelValues(name)
+}
+
+// startMetricsServer serves /metrics on METRICS_PORT (default 8080) in the background.
+func startMetricsServer() {
+	port := os.Getenv("METRICS_PORT")
+	if port == "" {
+		port = "8080"
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	go func() {
+		slog.Info("Serving metrics", "port", port, "path", "/metrics")
+		if err := http.ListenAndServe(":"+port, mux); err != nil {
+			slog.Error("Metrics server stopped", "err", err)
+		}
+	}()
+}
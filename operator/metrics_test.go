@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// reconcileCount returns how many reconciles were observed with result.
+func reconcileCount(t *testing.T, result string) uint64 {
+	t.Helper()
+	var m dto.Metric
+	if err := reconcileDuration.WithLabelValues(result).(prometheus.Histogram).Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestReconcileDurationResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		failGet bool
+		result  string
+	}{
+		{name: "success", result: "success"},
+		{name: "error", failGet: true, result: "error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := newSession("s1", nil, map[string]interface{}{"phase": "Completed"})
+			dyn, _ := useFakeClients(t, session)
+			if tt.failGet {
+				dyn.PrependReactor("get", "researchsessions", func(k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, fmt.Errorf("API server unavailable")
+				})
+			}
+
+			store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+			if err := store.Add(session); err != nil {
+				t.Fatal(err)
+			}
+			queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[string]())
+			defer queue.ShutDown()
+			queue.Add("default/s1")
+
+			before := reconcileCount(t, tt.result)
+			requeuesBefore := testCounterValue(t, reconcileRequeues)
+			if !processNextItem(context.Background(), queue, store) {
+				t.Fatal("processNextItem reported the queue as shut down")
+			}
+
+			if got := reconcileCount(t, tt.result) - before; got != 1 {
+				t.Errorf("%s observations = %d, want 1", tt.result, got)
+			}
+			wantRequeues := 0.0
+			if tt.failGet {
+				wantRequeues = 1
+			}
+			if got := testCounterValue(t, reconcileRequeues) - requeuesBefore; got != wantRequeues {
+				t.Errorf("requeues = %v, want %v", got, wantRequeues)
+			}
+		})
+	}
+}
+
+// testCounterValue reads the current value of counter.
+func testCounterValue(t *testing.T, counter prometheus.Counter) float64 {
+	t.Helper()
+	var m dto.Metric
+	if err := counter.Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+func TestQueueDepthMetric(t *testing.T) {
+	// A queue name of its own keeps the gauge apart from other tests' queues
+	queue := workqueue.NewTypedRateLimitingQueueWithConfig(
+		workqueue.DefaultTypedControllerRateLimiter[string](),
+		workqueue.TypedRateLimitingQueueConfig[string]{
+			Name:            "test-depth",
+			MetricsProvider: queueMetricsProvider{},
+		},
+	)
+	defer queue.ShutDown()
+	depth := func() float64 {
+		var m dto.Metric
+		if err := queueDepth.WithLabelValues("test-depth").Write(&m); err != nil {
+			t.Fatal(err)
+		}
+		return m.GetGauge().GetValue()
+	}
+	adds := testCounterValue(t, queueAdds.WithLabelValues("test-depth"))
+
+	for i := 0; i < 5; i++ {
+		queue.Add(fmt.Sprintf("default/s%d", i))
+	}
+	// A key already waiting is not queued twice
+	queue.Add("default/s0")
+	if got := depth(); got != 5 {
+		t.Errorf("depth after filling = %v, want 5", got)
+	}
+	if got := testCounterValue(t, queueAdds.WithLabelValues("test-depth")) - adds; got != 5 {
+		t.Errorf("adds = %v, want 5", got)
+	}
+
+	for i := 0; i < 3; i++ {
+		key, _ := queue.Get()
+		queue.Done(key)
+	}
+	if got := depth(); got != 2 {
+		t.Errorf("depth after draining 3 = %v, want 2", got)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// notifyAttempts is how many times a notification is sent before it is dropped
+const notifyAttempts = 3
+
+var (
+	// notifyWebhookURL receives a JSON POST when a session's job finishes; empty disables it
+	notifyWebhookURL string
+	notifyClient     = &http.Client{Timeout: 10 * time.Second}
+)
+
+func initNotifications() {
+	notifyWebhookURL = os.Getenv("NOTIFY_WEBHOOK_URL")
+	if notifyWebhookURL != "" {
+		slog.Info("Sending job outcome notifications", "url", notifyWebhookURL)
+	}
+}
+
+// sessionNotification is the webhook payload for a finished session.
+type sessionNotification struct {
+	Session         string   `json:"session"`
+	Namespace       string   `json:"namespace"`
+	Phase           string   `json:"phase"`
+	Message         string   `json:"message"`
+	WebsiteURL      string   `json:"websiteURL,omitempty"`
+	DurationSeconds *float64 `json:"durationSeconds,omitempty"`
+	CompletionTime  string   `json:"completionTime"`
+}
+
+// notifySessionFinished posts the session's terminal phase to
+// NOTIFY_WEBHOOK_URL in the background. For failures message carries the
+// truncated runner output. Delivery is retried a few times and then dropped,
+// so an unreachable webhook never holds up a monitor.
+func notifySessionFinished(session *unstructured.Unstructured, phase, message string) {
+	if notifyWebhookURL == "" {
+		return
+	}
+
+	now := time.Now()
+	notification := sessionNotification{
+		Session:        session.GetName(),
+		Namespace:      session.GetNamespace(),
+		Phase:          phase,
+		Message:        message,
+		CompletionTime: now.Format(time.RFC3339),
+	}
+	// Best effort: a mistyped field just leaves it out of the payload
+	notification.WebsiteURL, _, _ = unstructured.NestedString(session.Object, "spec", "websiteURL")
+	if startTime, _, _ := unstructured.NestedString(session.Object, "status", "startTime"); startTime != "" {
+		if started, err := time.Parse(time.RFC3339, startTime); err == nil {
+			duration := now.Sub(started).Seconds()
+			notification.DurationSeconds = &duration
+		}
+	}
+
+	logger := slog.With("namespace", notification.Namespace, "session", notification.Session, "phase", phase)
+	if dryRun {
+		logger.Info("[dry-run] Would send webhook notification", "url", notifyWebhookURL)
+		return
+	}
+
+	body, err := json.Marshal(notification)
+	if err != nil {
+		logger.Error("Failed to encode webhook notification", "err", err)
+		return
+	}
+	go func() {
+		for attempt := 1; ; attempt++ {
+			err := postNotification(body)
+			if err == nil {
+				return
+			}
+			if attempt == notifyAttempts {
+				logger.Warn("Dropping webhook notification", "attempts", attempt, "err", err)
+				return
+			}
+			time.Sleep(time.Duration(attempt) * 2 * time.Second)
+		}
+	}()
+}
+
+func postNotification(body []byte) error {
+	resp, err := notifyClient.Post(notifyWebhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
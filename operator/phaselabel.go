@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// phaseLabel mirrors status.phase so other controllers can select sessions by
+// phase with a label selector instead of watching status.
+const phaseLabel = "research.example.com/phase"
+
+// syncPhaseLabel sets the phase label on the session when it doesn't match
+// phase. Phases written by the backend or the runner are picked up the next
+// time the session is reconciled.
+func syncPhaseLabel(obj *unstructured.Unstructured, phase string) error {
+	if obj.GetLabels()[phaseLabel] == phase {
+		return nil
+	}
+
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{phaseLabel: phase},
+		},
+	}
+	if dryRun {
+		logDryRun(fmt.Sprintf("label ResearchSession %s/%s", obj.GetNamespace(), obj.GetName()), patch)
+		return nil
+	}
+
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return fmt.Errorf("failed to encode label patch: %v", err)
+	}
+	ctx, cancel := apiContext(context.Background())
+	defer cancel()
+	_, err = dynamicClient.Resource(getResearchSessionResource()).Namespace(obj.GetNamespace()).
+		Patch(ctx, obj.GetName(), types.MergePatchType, data, v1.PatchOptions{})
+	return err
+}
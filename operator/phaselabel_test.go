@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSyncPhaseLabelTracksPhase(t *testing.T) {
+	dyn, _ := useFakeClients(t, newSession("s1", nil, nil))
+	sessions := dyn.Resource(getResearchSessionResource()).Namespace("default")
+
+	for _, phase := range []string{"Pending", "Running", "Completed"} {
+		obj, err := sessions.Get(context.Background(), "s1", v1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := syncPhaseLabel(obj, phase); err != nil {
+			t.Fatalf("syncPhaseLabel(%s): %v", phase, err)
+		}
+
+		obj, err = sessions.Get(context.Background(), "s1", v1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := obj.GetLabels()[phaseLabel]; got != phase {
+			t.Errorf("after phase %s, label = %q", phase, got)
+		}
+	}
+}
+
+func TestSyncPhaseLabelSkipsMatchingLabel(t *testing.T) {
+	obj := newSession("s1", nil, nil)
+	obj.SetLabels(map[string]string{phaseLabel: "Running"})
+	dyn, _ := useFakeClients(t, obj)
+
+	if err := syncPhaseLabel(obj, "Running"); err != nil {
+		t.Fatal(err)
+	}
+	for _, action := range dyn.Actions() {
+		if action.GetVerb() == "patch" {
+			t.Errorf("unexpected patch when the label already matches")
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// promptVarPattern matches {{name}} placeholders, allowing spaces inside the braces
+var promptVarPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// resolvePrompt returns the session's prompt, read from spec.prompt or the
+// ConfigMap key named by spec.promptConfigMapRef, with spec.promptVars
+// substituted. Like checkAPIKeySecret it returns a user-facing problem for an
+// invalid spec and an error for API failures, which are worth retrying.
+func resolvePrompt(ctx context.Context, namespace string, spec map[string]interface{}) (string, string, error) {
+	prompt, _, err := specString(spec, "prompt")
+	if err != nil {
+		return "", err.Error(), nil
+	}
+
+	if _, found, err := specMap(spec, "promptConfigMapRef"); err != nil {
+		return "", err.Error(), nil
+	} else if found {
+		if prompt != "" {
+			return "", "Set either prompt or promptConfigMapRef, not both", nil
+		}
+		name, _, err := specString(spec, "promptConfigMapRef", "name")
+		if err != nil {
+			return "", err.Error(), nil
+		}
+		key, _, err := specString(spec, "promptConfigMapRef", "key")
+		if err != nil {
+			return "", err.Error(), nil
+		}
+		if name == "" || key == "" {
+			return "", "promptConfigMapRef requires both name and key", nil
+		}
+
+		reqCtx, cancel := apiContext(ctx)
+		defer cancel()
+		cm, err := k8sClient.CoreV1().ConfigMaps(namespace).Get(reqCtx, name, v1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return "", fmt.Sprintf("Prompt ConfigMap %q not found in namespace %s", name, namespace), nil
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read prompt ConfigMap %s: %v", name, err)
+		}
+		value, ok := cm.Data[key]
+		if !ok {
+			return "", fmt.Sprintf("Prompt ConfigMap %q has no key %q", name, key), nil
+		}
+		prompt = value
+	}
+
+	if strings.TrimSpace(prompt) == "" {
+		return "", "A prompt is required: set prompt or promptConfigMapRef", nil
+	}
+
+	vars, _, err := specStringMap(spec, "promptVars")
+	if err != nil {
+		return "", err.Error(), nil
+	}
+	prompt, missing := substitutePromptVars(prompt, vars)
+	if len(missing) > 0 {
+		return "", fmt.Sprintf("Prompt references undefined promptVars: %s", strings.Join(missing, ", ")), nil
+	}
+	return prompt, "", nil
+}
+
+// substitutePromptVars replaces {{name}} placeholders with values from vars,
+// returning the sorted names of placeholders that have no value.
+func substitutePromptVars(prompt string, vars map[string]string) (string, []string) {
+	missing := map[string]bool{}
+	result := promptVarPattern.ReplaceAllStringFunc(prompt, func(placeholder string) string {
+		name := promptVarPattern.FindStringSubmatch(placeholder)[1]
+		value, ok := vars[name]
+		if !ok {
+			missing[name] = true
+			return placeholder
+		}
+		return value
+	})
+
+	var names []string
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return result, names
+}
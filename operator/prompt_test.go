@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSubstitutePromptVars(t *testing.T) {
+	got, missing := substitutePromptVars("Research {{topic}} on {{ site }} for {{audience}} and {{year}}", map[string]string{
+		"topic": "pricing",
+		"site":  "example.com",
+	})
+	if want := "Research pricing on example.com for {{audience}} and {{year}}"; got != want {
+		t.Errorf("prompt = %q, want %q", got, want)
+	}
+	if want := []string{"audience", "year"}; !reflect.DeepEqual(missing, want) {
+		t.Errorf("missing = %v, want %v", missing, want)
+	}
+}
+
+func TestResolvePrompt(t *testing.T) {
+	useFakeClients(t, &corev1.ConfigMap{
+		ObjectMeta: v1.ObjectMeta{Name: "prompts", Namespace: "default"},
+		Data:       map[string]string{"summary": "Summarize {{site}}"},
+	})
+
+	tests := []struct {
+		name    string
+		spec    map[string]interface{}
+		prompt  string
+		problem string
+	}{
+		{
+			name:   "inline",
+			spec:   map[string]interface{}{"prompt": "Summarize the site"},
+			prompt: "Summarize the site",
+		},
+		{
+			name: "configmap with vars",
+			spec: map[string]interface{}{
+				"promptConfigMapRef": map[string]interface{}{"name": "prompts", "key": "summary"},
+				"promptVars":         map[string]interface{}{"site": "example.com"},
+			},
+			prompt: "Summarize example.com",
+		},
+		{
+			name:    "both",
+			spec:    map[string]interface{}{"prompt": "x", "promptConfigMapRef": map[string]interface{}{"name": "prompts", "key": "summary"}},
+			problem: "Set either prompt or promptConfigMapRef, not both",
+		},
+		{
+			name:    "missing key",
+			spec:    map[string]interface{}{"promptConfigMapRef": map[string]interface{}{"name": "prompts", "key": "other"}},
+			problem: `Prompt ConfigMap "prompts" has no key "other"`,
+		},
+		{
+			name:    "missing configmap",
+			spec:    map[string]interface{}{"promptConfigMapRef": map[string]interface{}{"name": "absent", "key": "summary"}},
+			problem: `Prompt ConfigMap "absent" not found in namespace default`,
+		},
+		{
+			name:    "undefined var",
+			spec:    map[string]interface{}{"promptConfigMapRef": map[string]interface{}{"name": "prompts", "key": "summary"}},
+			problem: "Prompt references undefined promptVars: site",
+		},
+		{
+			name:    "empty",
+			spec:    map[string]interface{}{"prompt": "  "},
+			problem: "A prompt is required: set prompt or promptConfigMapRef",
+		},
+	}
+	for _, tt := range tests {
+		prompt, problem, err := resolvePrompt(context.Background(), "default", tt.spec)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if prompt != tt.prompt || problem != tt.problem {
+			t.Errorf("%s: got %q, %q; want %q, %q", tt.name, prompt, problem, tt.prompt, tt.problem)
+		}
+	}
+}
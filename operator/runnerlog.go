@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// runnerLogLimitBytes caps status.runnerLog so a chatty runner can't bloat the
+// session object.
+const runnerLogLimitBytes = 16 * 1024
+
+// runnerLogTailLines is how many of the runner's latest log lines monitorJob
+// copies into status.runnerLog while the job runs; 0 disables it.
+var runnerLogTailLines int64
+
+// tailRunnerLog returns the last runnerLogTailLines lines of the newest runner
+// pod's log. ok is false when there is nothing to show yet, e.g. the pod
+// hasn't been scheduled or its container hasn't started.
+func tailRunnerLog(ctx context.Context, namespace, jobName string) (string, bool, error) {
+	reqCtx, cancel := apiContext(ctx)
+	defer cancel()
+
+	pods, err := k8sClient.CoreV1().Pods(namespace).List(reqCtx, v1.ListOptions{
+		LabelSelector: fmt.Sprintf("job-name=%s", jobName),
+	})
+	if err != nil {
+		return "", false, fmt.Errorf("failed to list pods for job %s: %v", jobName, err)
+	}
+
+	var newest *corev1.Pod
+	for i := range pods.Items {
+		if newest == nil || podNewer(&pods.Items[i], newest) {
+			newest = &pods.Items[i]
+		}
+	}
+	if newest == nil || !runnerStarted(newest) {
+		return "", false, nil
+	}
+
+	logs, err := k8sClient.CoreV1().Pods(namespace).GetLogs(newest.Name, &corev1.PodLogOptions{
+		Container:  "claude-runner",
+		TailLines:  &runnerLogTailLines,
+		LimitBytes: int64Ptr(runnerLogLimitBytes),
+	}).DoRaw(reqCtx)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get logs for pod %s: %v", newest.Name, err)
+	}
+	return string(logs), true, nil
+}
+
+// runnerStarted reports whether the runner container has started, so it has
+// logs to read.
+func runnerStarted(pod *corev1.Pod) bool {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name == "claude-runner" {
+			return cs.State.Running != nil || cs.State.Terminated != nil
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// runnerScheduling is where runner pods may be scheduled.
+type runnerScheduling struct {
+	nodeSelector map[string]string
+	tolerations  []corev1.Toleration
+	affinity     *corev1.Affinity
+}
+
+// defaultScheduling comes from RUNNER_NODE_SELECTOR, RUNNER_TOLERATIONS and
+// RUNNER_AFFINITY; a session's own field replaces the matching default.
+var defaultScheduling runnerScheduling
+
+func initRunnerScheduling() {
+	for env, out := range map[string]interface{}{
+		"RUNNER_NODE_SELECTOR": &defaultScheduling.nodeSelector,
+		"RUNNER_TOLERATIONS":   &defaultScheduling.tolerations,
+		"RUNNER_AFFINITY":      &defaultScheduling.affinity,
+	} {
+		v := os.Getenv(env)
+		if v == "" {
+			continue
+		}
+		decoder := json.NewDecoder(bytes.NewReader([]byte(v)))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(out); err != nil {
+			fatal("Invalid "+env, "err", err)
+		}
+	}
+	if err := validateTolerations(defaultScheduling.tolerations); err != nil {
+		fatal("Invalid RUNNER_TOLERATIONS", "err", err)
+	}
+}
+
+// runnerSchedulingFor reads spec.nodeSelector, spec.tolerations and
+// spec.affinity over the defaults.
+func runnerSchedulingFor(spec map[string]interface{}) (runnerScheduling, error) {
+	result := defaultScheduling
+
+	var nodeSelector map[string]string
+	if found, err := specDecode(spec, &nodeSelector, "nodeSelector"); err != nil {
+		return result, err
+	} else if found {
+		result.nodeSelector = nodeSelector
+	}
+
+	var tolerations []corev1.Toleration
+	if found, err := specDecode(spec, &tolerations, "tolerations"); err != nil {
+		return result, err
+	} else if found {
+		if err := validateTolerations(tolerations); err != nil {
+			return result, invalidSpec("tolerations", fmt.Sprintf("spec.tolerations is invalid: %v", err))
+		}
+		result.tolerations = tolerations
+	}
+
+	var affinity corev1.Affinity
+	if found, err := specDecode(spec, &affinity, "affinity"); err != nil {
+		return result, err
+	} else if found {
+		result.affinity = &affinity
+	}
+
+	return result, nil
+}
+
+// validateTolerations checks the enum fields the API server would otherwise
+// reject when the job's pods are created.
+func validateTolerations(tolerations []corev1.Toleration) error {
+	for i, t := range tolerations {
+		switch t.Operator {
+		case "", corev1.TolerationOpEqual:
+		case corev1.TolerationOpExists:
+			if t.Value != "" {
+				return fmt.Errorf("[%d]: value must be empty with operator Exists", i)
+			}
+		default:
+			return fmt.Errorf("[%d]: operator must be Equal or Exists, got %q", i, t.Operator)
+		}
+		switch t.Effect {
+		case "", corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+		default:
+			return fmt.Errorf("[%d]: effect must be NoSchedule, PreferNoSchedule or NoExecute, got %q", i, t.Effect)
+		}
+		if t.Key == "" && t.Operator != corev1.TolerationOpExists {
+			return fmt.Errorf("[%d]: an empty key requires operator Exists", i)
+		}
+	}
+	return nil
+}
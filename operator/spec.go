@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// The spec accessors below read a field from a ResearchSession spec. Unlike
+// unstructured.Nested*, a field that is present with the wrong type is an
+// error rather than a zero value, so a mistyped spec fails the session instead
+// of silently running with defaults. Absent and null fields report found=false.
+
+// specField walks fields through nested objects in spec.
+func specField(spec map[string]interface{}, fields ...string) (interface{}, bool, error) {
+	var value interface{} = spec
+	for i, field := range fields {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false, specTypeError(fields[:i], "an object", value)
+		}
+		if value, ok = m[field]; !ok || value == nil {
+			return nil, false, nil
+		}
+	}
+	return value, true, nil
+}
+
+func specString(spec map[string]interface{}, fields ...string) (string, bool, error) {
+	value, found, err := specField(spec, fields...)
+	if err != nil || !found {
+		return "", false, err
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", false, specTypeError(fields, "a string", value)
+	}
+	return s, true, nil
+}
+
+// specInt64 also accepts a whole float64, which is how JSON numbers such as
+// 300.0 decode.
+func specInt64(spec map[string]interface{}, fields ...string) (int64, bool, error) {
+	value, found, err := specField(spec, fields...)
+	if err != nil || !found {
+		return 0, false, err
+	}
+	switch v := value.(type) {
+	case int64:
+		return v, true, nil
+	case float64:
+		if v == math.Trunc(v) {
+			return int64(v), true, nil
+		}
+	}
+	return 0, false, specTypeError(fields, "an integer", value)
+}
+
+func specMap(spec map[string]interface{}, fields ...string) (map[string]interface{}, bool, error) {
+	value, found, err := specField(spec, fields...)
+	if err != nil || !found {
+		return nil, false, err
+	}
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, false, specTypeError(fields, "an object", value)
+	}
+	return m, true, nil
+}
+
+func specStringMap(spec map[string]interface{}, fields ...string) (map[string]string, bool, error) {
+	m, found, err := specMap(spec, fields...)
+	if err != nil || !found {
+		return nil, false, err
+	}
+	result := make(map[string]string, len(m))
+	for key, value := range m {
+		s, ok := value.(string)
+		if !ok {
+			return nil, false, specTypeError(append(fields[:len(fields):len(fields)], key), "a string", value)
+		}
+		result[key] = s
+	}
+	return result, true, nil
+}
+
+func specSlice(spec map[string]interface{}, fields ...string) ([]interface{}, bool, error) {
+	value, found, err := specField(spec, fields...)
+	if err != nil || !found {
+		return nil, false, err
+	}
+	s, ok := value.([]interface{})
+	if !ok {
+		return nil, false, specTypeError(fields, "a list", value)
+	}
+	return s, true, nil
+}
+
+// specDecode decodes the field into out, a Kubernetes API type, rejecting
+// fields the type doesn't have so a typo fails the session instead of being
+// dropped.
+func specDecode(spec map[string]interface{}, out interface{}, fields ...string) (bool, error) {
+	value, found, err := specField(spec, fields...)
+	if err != nil || !found {
+		return false, err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(out); err != nil {
+		return false, invalidSpec(fields[0], fmt.Sprintf("%s is invalid: %v", strings.Join(append([]string{"spec"}, fields...), "."), err))
+	}
+	return true, nil
+}
+
+// specTypeError describes a mistyped field in the terms a user wrote it, e.g.
+// "spec.timeout must be an integer, got string".
+func specTypeError(fields []string, want string, value interface{}) error {
+	field := ""
+	if len(fields) > 0 {
+		field = fields[0]
+	}
+	return invalidSpec(field, fmt.Sprintf("%s must be %s, got %s", strings.Join(append([]string{"spec"}, fields...), "."), want, jsonType(value)))
+}
+
+// specError is a spec problem attributed to the top-level field it was found
+// in, which names the SpecValid condition's reason.
+type specError struct {
+	field   string
+	message string
+}
+
+func (e *specError) Error() string {
+	return e.message
+}
+
+// invalidSpec reports a problem with spec.field. An empty field means the
+// spec as a whole.
+func invalidSpec(field, message string) error {
+	return &specError{field: field, message: message}
+}
+
+// specErrorReason turns the field behind err into a condition reason such as
+// InvalidTimeout, or InvalidSpec when no field is known.
+func specErrorReason(err error) string {
+	var se *specError
+	if !errors.As(err, &se) || se.field == "" {
+		return "InvalidSpec"
+	}
+	field, _, _ := strings.Cut(se.field, "[")
+	return "Invalid" + strings.ToUpper(field[:1]) + field[1:]
+}
+
+// jsonType names the JSON type of an unstructured value.
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case int64, float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "list"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// failInvalidSpec marks the session Failed because its spec could not be read.
+func failInvalidSpec(ctx context.Context, obj *unstructured.Unstructured, err error) error {
+	loggerFrom(ctx).Warn("Invalid spec", "phase", "Failed", "err", err)
+	return rejectSpec(obj, err)
+}
+
+// rejectSpec marks the session Failed with SpecValid=False, naming the field
+// at fault in the reason.
+func rejectSpec(obj *unstructured.Unstructured, err error) error {
+	return failSession(obj, err.Error(), conditionUpdate{conditionSpecValid, "False", specErrorReason(err), err.Error()})
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestSpecInt64(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      map[string]interface{}
+		want      int64
+		wantFound bool
+		wantErr   string
+	}{
+		{name: "int64", spec: map[string]interface{}{"timeout": int64(300)}, want: 300, wantFound: true},
+		{name: "whole float", spec: map[string]interface{}{"timeout": 300.0}, want: 300, wantFound: true},
+		{name: "absent", spec: map[string]interface{}{}},
+		{name: "null", spec: map[string]interface{}{"timeout": nil}},
+		{name: "fractional", spec: map[string]interface{}{"timeout": 2.5}, wantErr: "spec.timeout must be an integer, got number"},
+		{name: "string", spec: map[string]interface{}{"timeout": "300"}, wantErr: "spec.timeout must be an integer, got string"},
+	}
+	for _, tt := range tests {
+		got, found, err := specInt64(tt.spec, "timeout")
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("%s: err = %v, want %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want || found != tt.wantFound {
+			t.Errorf("%s: got %d, %v, %v; want %d, %v, nil", tt.name, got, found, err, tt.want, tt.wantFound)
+		}
+	}
+}
+
+func TestSpecString(t *testing.T) {
+	spec := map[string]interface{}{
+		"prompt":      "Summarize the site",
+		"websiteURL":  int64(42),
+		"llmSettings": "claude",
+	}
+
+	if got, found, err := specString(spec, "prompt"); err != nil || !found || got != "Summarize the site" {
+		t.Errorf("prompt: got %q, %v, %v", got, found, err)
+	}
+	if _, _, err := specString(spec, "websiteURL"); err == nil || err.Error() != "spec.websiteURL must be a string, got number" {
+		t.Errorf("websiteURL: err = %v", err)
+	}
+	// A scalar where an object is expected is reported at the scalar
+	if _, _, err := specString(spec, "llmSettings", "model"); err == nil || err.Error() != "spec.llmSettings must be an object, got string" {
+		t.Errorf("llmSettings.model: err = %v", err)
+	}
+	if _, found, err := specString(spec, "missing", "model"); err != nil || found {
+		t.Errorf("missing.model: found = %v, err = %v", found, err)
+	}
+}
+
+func TestSpecStringMap(t *testing.T) {
+	spec := map[string]interface{}{"promptVars": map[string]interface{}{"topic": "go", "count": int64(3)}}
+
+	if _, _, err := specStringMap(spec, "promptVars"); err == nil || err.Error() != "spec.promptVars.count must be a string, got number" {
+		t.Errorf("err = %v", err)
+	}
+}
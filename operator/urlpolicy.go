@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+)
+
+// defaultDeniedCIDRs keeps the runner away from loopback, private, link-local
+// (including the cloud metadata endpoint 169.254.169.254) and other
+// non-public addresses
+var defaultDeniedCIDRs = []string{
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"::/128",
+	"::1/128",
+	"fc00::/7",
+	"fe80::/10",
+}
+
+var (
+	// allowedURLHosts restricts spec.websiteURL hosts to these patterns
+	// (e.g. example.com, *.example.com); empty allows any host
+	allowedURLHosts []string
+	// deniedURLNets rejects spec.websiteURL hosts that are, or resolve to, these networks
+	deniedURLNets []*net.IPNet
+
+	// lookupIPAddr resolves websiteURL hosts; tests replace it
+	lookupIPAddr = net.DefaultResolver.LookupIPAddr
+)
+
+func initURLPolicy() {
+	for _, pattern := range strings.Split(os.Getenv("URL_ALLOWED_HOSTS"), ",") {
+		if pattern = strings.ToLower(strings.TrimSpace(pattern)); pattern != "" {
+			allowedURLHosts = append(allowedURLHosts, pattern)
+		}
+	}
+
+	cidrs := defaultDeniedCIDRs
+	if v, ok := os.LookupEnv("URL_DENIED_CIDRS"); ok {
+		cidrs = strings.Split(v, ",")
+	}
+	for _, cidr := range cidrs {
+		if cidr = strings.TrimSpace(cidr); cidr == "" {
+			continue
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			fatal("Invalid URL_DENIED_CIDRS entry", "value", cidr, "err", err)
+		}
+		deniedURLNets = append(deniedURLNets, network)
+	}
+}
+
+// checkWebsiteURL applies the URL policy to spec.websiteURL, returning a
+// user-facing problem if the runner must not visit it. Hostnames are resolved
+// here so names pointing at internal addresses are caught too; this doesn't
+// stop a name that changes what it resolves to after the check. Like
+// checkAPIKeySecret, a host that doesn't exist is a problem while other
+// resolver failures are errors, which are worth retrying.
+func checkWebsiteURL(ctx context.Context, rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return fmt.Sprintf("websiteURL %q is not a valid URL", rawURL), nil
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Sprintf("websiteURL scheme %q is not allowed; use http or https", u.Scheme), nil
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if len(allowedURLHosts) > 0 && !hostAllowed(host) {
+		return fmt.Sprintf("websiteURL host %q is not in the allowed hosts", host), nil
+	}
+
+	var ips []net.IP
+	if ip := net.ParseIP(host); ip != nil {
+		ips = []net.IP{ip}
+	} else {
+		reqCtx, cancel := apiContext(ctx)
+		defer cancel()
+		addrs, err := lookupIPAddr(reqCtx, host)
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return fmt.Sprintf("websiteURL host %q could not be resolved: %v", host, err), nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve websiteURL host %s: %v", host, err)
+		}
+		for _, addr := range addrs {
+			ips = append(ips, addr.IP)
+		}
+	}
+
+	for _, ip := range ips {
+		for _, network := range deniedURLNets {
+			if network.Contains(ip) {
+				return fmt.Sprintf("websiteURL host %q resolves to %s, which is in a denied network (%s)", host, ip, network), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// hostAllowed reports whether host matches one of allowedURLHosts. A
+// "*.example.com" pattern matches subdomains but not example.com itself.
+func hostAllowed(host string) bool {
+	for _, pattern := range allowedURLHosts {
+		if matched, err := path.Match(pattern, host); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
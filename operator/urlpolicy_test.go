@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// useURLPolicy applies the URL policy from the given allowed hosts and the
+// default denied networks for the duration of the test.
+func useURLPolicy(t *testing.T, allowedHosts string) {
+	t.Helper()
+	prevAllowed, prevDenied, prevTimeout := allowedURLHosts, deniedURLNets, apiTimeout
+	t.Cleanup(func() {
+		allowedURLHosts, deniedURLNets, apiTimeout = prevAllowed, prevDenied, prevTimeout
+	})
+
+	allowedURLHosts, deniedURLNets, apiTimeout = nil, nil, 10*time.Second
+	t.Setenv("URL_ALLOWED_HOSTS", allowedHosts)
+	initURLPolicy()
+}
+
+func TestCheckWebsiteURL(t *testing.T) {
+	useURLPolicy(t, "")
+
+	tests := []struct {
+		url     string
+		problem string // substring of the expected problem; empty means allowed
+	}{
+		{url: "http://localhost:8080/admin", problem: "denied network"},
+		{url: "http://127.0.0.1/", problem: "denied network"},
+		{url: "http://169.254.169.254/latest/meta-data/", problem: "denied network"},
+		{url: "http://[::1]/", problem: "denied network"},
+		{url: "http://[::]/", problem: "denied network"},
+		{url: "http://10.0.0.5/", problem: "denied network"},
+		{url: "ftp://example.com/file", problem: "scheme"},
+		{url: "not a url", problem: "not a valid URL"},
+		{url: "https://93.184.216.34/"},
+	}
+	for _, tt := range tests {
+		problem, err := checkWebsiteURL(context.Background(), tt.url)
+		if err != nil {
+			t.Errorf("checkWebsiteURL(%q): %v", tt.url, err)
+			continue
+		}
+		if tt.problem == "" && problem != "" {
+			t.Errorf("checkWebsiteURL(%q) = %q, want allowed", tt.url, problem)
+		}
+		if tt.problem != "" && !strings.Contains(problem, tt.problem) {
+			t.Errorf("checkWebsiteURL(%q) = %q, want a problem mentioning %q", tt.url, problem, tt.problem)
+		}
+	}
+}
+
+func TestCheckWebsiteURLAllowedHosts(t *testing.T) {
+	useURLPolicy(t, "*.example.com")
+
+	if problem, _ := checkWebsiteURL(context.Background(), "https://example.org/"); !strings.Contains(problem, "not in the allowed hosts") {
+		t.Errorf("example.org: got %q, want it rejected by the allowed hosts", problem)
+	}
+	if !hostAllowed("docs.example.com") {
+		t.Error("docs.example.com should match *.example.com")
+	}
+	if hostAllowed("example.com") {
+		t.Error("example.com should not match *.example.com")
+	}
+}
+
+func TestCheckWebsiteURLResolverErrors(t *testing.T) {
+	useURLPolicy(t, "")
+	prevLookup := lookupIPAddr
+	t.Cleanup(func() { lookupIPAddr = prevLookup })
+
+	// A name that doesn't exist won't start existing on retry
+	lookupIPAddr = func(context.Context, string) ([]net.IPAddr, error) {
+		return nil, &net.DNSError{Err: "no such host", Name: "nope.example.com", IsNotFound: true}
+	}
+	problem, err := checkWebsiteURL(context.Background(), "https://nope.example.com/")
+	if err != nil || !strings.Contains(problem, "could not be resolved") {
+		t.Errorf("NXDOMAIN: got %q, %v; want a problem", problem, err)
+	}
+
+	// A timeout or SERVFAIL says nothing about the spec, so it must be retried
+	lookupIPAddr = func(context.Context, string) ([]net.IPAddr, error) {
+		return nil, &net.DNSError{Err: "i/o timeout", Name: "example.com", IsTimeout: true}
+	}
+	problem, err = checkWebsiteURL(context.Background(), "https://example.com/")
+	if err == nil || problem != "" {
+		t.Errorf("timeout: got %q, %v; want an error", problem, err)
+	}
+
+	lookupIPAddr = func(context.Context, string) ([]net.IPAddr, error) {
+		return []net.IPAddr{{IP: net.ParseIP("10.1.2.3")}}, nil
+	}
+	if problem, err := checkWebsiteURL(context.Background(), "https://internal.example.com/"); err != nil || !strings.Contains(problem, "denied network") {
+		t.Errorf("internal name: got %q, %v; want a denied network problem", problem, err)
+	}
+}
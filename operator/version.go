@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Build information, set at build time with
+// -ldflags "-X main.version=... -X main.commit=... -X main.buildDate=..."
+var (
+	version   = "dev"
+	commit    = "unknown"
+	buildDate = "unknown"
+)
+
+// buildInfo is always 1; the build is described by its labels
+var buildInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "research_operator_build_info",
+	Help: "Operator build information, always 1.",
+}, []string{"version", "commit", "build_date", "go_version"})
+
+type versionInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"buildDate"`
+	GoVersion string `json:"goVersion"`
+}
+
+func currentVersion() versionInfo {
+	return versionInfo{
+		Version:   version,
+		Commit:    commit,
+		BuildDate: buildDate,
+		GoVersion: runtime.Version(),
+	}
+}
+
+func (v versionInfo) String() string {
+	return fmt.Sprintf("research-operator %s (commit %s, built %s, %s)", v.Version, v.Commit, v.BuildDate, v.GoVersion)
+}
+
+// registerVersionHandler adds GET /version, which reports the build as JSON.
+func registerVersionHandler(mux *http.ServeMux) {
+	mux.HandleFunc("/version", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(currentVersion())
+	})
+}